@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"ride-sharing/shared/contracts"
+)
+
+// wsConn serialises writes to a websocket connection
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// WriteJSON sends a message to the client
+func (c *wsConn) WriteJSON(v any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+// ConnectionManager keeps track of the open websocket connection of every rider and driver
+type ConnectionManager struct {
+	connections map[string]*wsConn
+	mu          sync.RWMutex
+}
+
+// NewConnectionManager creates an empty connection manager
+func NewConnectionManager() *ConnectionManager {
+	return &ConnectionManager{
+		connections: make(map[string]*wsConn),
+	}
+}
+
+// Add registers the connection of a user, replacing any previous one
+func (cm *ConnectionManager) Add(userID string, conn *websocket.Conn) *wsConn {
+	c := &wsConn{conn: conn}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if old, ok := cm.connections[userID]; ok {
+		old.conn.Close()
+	}
+	cm.connections[userID] = c
+	return c
+}
+
+// Remove unregisters the connection of a user if it is still the current one
+func (cm *ConnectionManager) Remove(userID string, c *wsConn) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.connections[userID] == c {
+		delete(cm.connections, userID)
+	}
+}
+
+// Send delivers a message to a user, reporting whether they are connected to this gateway
+func (cm *ConnectionManager) Send(userID string, msg contracts.WSMessage) (bool, error) {
+	cm.mu.RLock()
+	c, ok := cm.connections[userID]
+	cm.mu.RUnlock()
+
+	if !ok {
+		return false, nil
+	}
+	return true, c.WriteJSON(msg)
+}
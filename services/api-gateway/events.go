@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/retry"
+)
+
+// EventBus connects the gateway to the trip exchange on RabbitMQ
+type EventBus struct {
+	conn *messaging.Connection
+}
+
+// NewEventBus dials RabbitMQ and declares the trip exchange.
+// The connection is re-established, and the exchange and notification queue declared again, whenever it drops.
+func NewEventBus(ctx context.Context, uri string) (*EventBus, error) {
+	conn, err := messaging.Dial(ctx, uri, retry.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	// Declare trip exchange
+	err = conn.DeclareExchange(messaging.Exchange{Name: "trip_exchange", Kind: "topic"})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &EventBus{
+		conn: conn,
+	}, nil
+}
+
+// Close closes the connection
+func (b *EventBus) Close() error {
+	return b.conn.Close()
+}
+
+// producerName identifies the gateway in the envelope of the messages it publishes
+const producerName = "api-gateway"
+
+// publishRetry is how often a message the broker did not take is published again before giving up
+var publishRetry = retry.Config{
+	MaxRetries:  2,
+	InitialWait: 100 * time.Millisecond,
+	MaxWait:     1 * time.Second,
+}
+
+// Send publishes data to the trip exchange in the versioned envelope, on behalf of ownerID, implementing
+// messaging.Sender. data must be the payload type of the routing key. The envelope carries the trace and
+// correlation ID of ctx and a unique ID consumers deduplicate on.
+// It waits until the broker has taken the message and retries briefly when it did not; a message no
+// queue is bound for is not retried and comes back as a messaging.UnroutableError.
+func (b *EventBus) Send(ctx context.Context, routingKey, ownerID string, data any) error {
+	envelope, err := messaging.NewMessage(ctx, producerName, routingKey, ownerID, data)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		MessageId:   envelope.ID,
+		Timestamp:   envelope.OccurredAt,
+		Body:        body,
+	}
+	err = retry.WithBackoff(ctx, publishRetry, func() error {
+		err := b.conn.Publish(ctx, "trip_exchange", routingKey, msg)
+		if errors.Is(err, messaging.ErrUnroutable) {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish %s: %w", routingKey, err)
+	}
+	return nil
+}
+
+// notifications routes the trip events to the websockets of the riders and drivers they concern
+func notifications(connections *ConnectionManager) *messaging.Router {
+	router := messaging.NewRouter()
+
+	notify(router, connections, contracts.TripEventCreatedTopic, func(data contracts.TripEventCreatedData) []string {
+		return tripRider(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventScheduledTopic, func(data contracts.TripEventScheduledData) []string {
+		return tripRider(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventScheduledReminderTopic, func(data contracts.TripEventScheduledReminderData) []string {
+		return tripRider(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventDriverAssignedTopic, func(data contracts.TripEventDriverAssignedData) []string {
+		return tripRider(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventNoDriversFoundTopic, func(data contracts.TripEventNoDriversFoundData) []string {
+		return []string{data.UserID}
+	})
+	notify(router, connections, contracts.TripEventPoolRiderJoinedTopic, func(data contracts.TripEventPoolRiderJoinedData) []string {
+		return tripRiderAndDriver(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventPoolRiderLeftTopic, func(data contracts.TripEventPoolRiderLeftData) []string {
+		trip := data.Trip
+		if trip == nil {
+			return nil
+		}
+
+		// The rider who left is told too
+		recipients := make([]string, 0, len(trip.Riders)+1)
+		for _, rider := range trip.Riders {
+			recipients = append(recipients, rider.UserID)
+		}
+		if trip.Driver != nil {
+			recipients = append(recipients, trip.Driver.ID)
+		}
+		return recipients
+	})
+	notify(router, connections, contracts.TripEventCancelledTopic, func(data contracts.TripEventCancelledData) []string {
+		return tripRiderAndDriver(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventStartedTopic, func(data contracts.TripEventStartedData) []string {
+		return tripRiderAndDriver(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventStopReachedTopic, func(data contracts.TripEventStopReachedData) []string {
+		return tripRiderAndDriver(data.TripPayload)
+	})
+	notify(router, connections, contracts.TripEventCompletedTopic, func(data contracts.TripEventCompletedData) []string {
+		return tripRiderAndDriver(data.TripPayload)
+	})
+	notify(router, connections, contracts.DriverCmdTripRequestTopic, func(data contracts.DriverCmdTripRequestData) []string {
+		return []string{data.DriverID}
+	})
+
+	return router
+}
+
+// notify forwards the messages of a topic to the users recipientsOf picks from their payload.
+// The payload is forwarded as the websocket data, in the shape of its current schema version.
+func notify[T any](router *messaging.Router, connections *ConnectionManager, topic contracts.Topic[T], recipientsOf func(data T) []string) {
+	messaging.Subscribe(router, topic, func(_ context.Context, _ *contracts.AmqpMessage, data T) error {
+		wsMsg := contracts.WSMessage{
+			Type: topic.Key,
+			Data: data,
+		}
+
+		for _, userID := range recipientsOf(data) {
+			if userID == "" {
+				continue
+			}
+			if _, err := connections.Send(userID, wsMsg); err != nil {
+				log.Printf("❌ Failed to send %s to %s: %v", topic.Key, userID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func tripRider(payload contracts.TripPayload) []string {
+	if payload.Trip == nil {
+		return nil
+	}
+	return tripRiders(payload.Trip)
+}
+
+func tripRiderAndDriver(payload contracts.TripPayload) []string {
+	trip := payload.Trip
+	if trip == nil {
+		return nil
+	}
+
+	recipients := tripRiders(trip)
+	if trip.Driver != nil {
+		recipients = append(recipients, trip.Driver.ID)
+	}
+	return recipients
+}
+
+// tripRiders returns the rider of a trip, or every rider still sharing a pool trip
+func tripRiders(trip *tripTypes.Trip) []string {
+	if len(trip.Riders) == 0 {
+		return []string{trip.UserID}
+	}
+
+	riders := make([]string, 0, len(trip.Riders))
+	for _, rider := range trip.Riders {
+		if rider.Cancellation == nil {
+			riders = append(riders, rider.UserID)
+		}
+	}
+	return riders
+}
+
+// StartNotificationConsumer forwards trip events to the websockets of the riders and drivers they concern.
+// Every gateway instance gets its own exclusive queue since each one holds different connections.
+func (b *EventBus) StartNotificationConsumer(ctx context.Context, connections *ConnectionManager) error {
+	// Named per instance rather than by the server, so the same queue is declared again after a reconnect
+	queue := messaging.Queue{
+		Name:      "gateway_notifications." + uuid.NewString(),
+		Transient: true,
+		Exclusive: true,
+	}
+	router := notifications(connections)
+	for _, routingKey := range router.Keys() {
+		queue.Bindings = append(queue.Bindings, messaging.Binding{Exchange: "trip_exchange", RoutingKey: routingKey})
+	}
+
+	if err := b.conn.DeclareQueue(queue); err != nil {
+		return fmt.Errorf("failed to declare notification queue: %w", err)
+	}
+
+	msgs, err := b.conn.Consume(ctx, queue.Name, messaging.ConsumeOptions{AutoAck: true, Exclusive: true})
+	if err != nil {
+		return fmt.Errorf("failed to register notification consumer: %w", err)
+	}
+
+	go func() {
+		for msg := range msgs {
+			// Legacy bare payloads and every schema version up to the current one are forwarded
+			if err := router.Handle(ctx, msg); err != nil {
+				log.Printf("❌ Failed to forward %s: %v", msg.RoutingKey, err)
+			}
+		}
+	}()
+
+	log.Println("📨 Started websocket notification consumer")
+	return nil
+}
@@ -0,0 +1,45 @@
+package grpc_clients
+
+import (
+	"fmt"
+
+	pb "ride-sharing/proto/trip"
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TripServiceClient wraps the gRPC client for the trip service
+type TripServiceClient struct {
+	Client pb.TripServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewTripServiceClient creates a new trip service gRPC client
+func NewTripServiceClient() (*TripServiceClient, error) {
+	tripServiceURL := env.GetString("TRIP_SERVICE_URL", "trip-service:8083")
+
+	conn, err := grpc.NewClient(tripServiceURL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor), // passes trace and correlation ID on
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trip service client: %w", err)
+	}
+
+	return &TripServiceClient{
+		Client: pb.NewTripServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// Close closes the underlying gRPC connection
+func (c *TripServiceClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
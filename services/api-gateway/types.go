@@ -0,0 +1,200 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	pb "ride-sharing/proto/trip"
+	"ride-sharing/shared/types"
+)
+
+// previewTripRequest is the payload sent by the frontend to /trip/preview
+type previewTripRequest struct {
+	UserID      string             `json:"userID"`
+	Pickup      types.Coordinate   `json:"pickup"`
+	Destination types.Coordinate   `json:"destination"`
+	Waypoints   []types.Coordinate `json:"waypoints,omitempty"` // stops on the way, in order
+	PromoCode   string             `json:"promoCode,omitempty"`
+	PickupAt    *time.Time         `json:"pickupAt,omitempty"` // set to book the ride ahead
+}
+
+// toProto converts the HTTP payload into a PreviewTripRequest
+func (r *previewTripRequest) toProto() *pb.PreviewTripRequest {
+	return &pb.PreviewTripRequest{
+		UserId: r.UserID,
+		Pickup: &pb.Coordinate{
+			Latitude:  r.Pickup.Latitude,
+			Longitude: r.Pickup.Longitude,
+		},
+		Destination: &pb.Coordinate{
+			Latitude:  r.Destination.Latitude,
+			Longitude: r.Destination.Longitude,
+		},
+		Waypoints: waypointsToProto(r.Waypoints),
+		PromoCode: r.PromoCode,
+		PickupAt:  unixOrZero(r.PickupAt),
+	}
+}
+
+// unixOrZero converts an optional time to a Unix timestamp, 0 when unset
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+func waypointsToProto(waypoints []types.Coordinate) []*pb.Coordinate {
+	result := make([]*pb.Coordinate, 0, len(waypoints))
+	for _, w := range waypoints {
+		result = append(result, &pb.Coordinate{Latitude: w.Latitude, Longitude: w.Longitude})
+	}
+	return result
+}
+
+// previewTripResponse mirrors HTTPTripPreviewResponse in web/src/contracts.ts
+type previewTripResponse struct {
+	Route     *types.Route       `json:"route"`
+	RideFares []*types.RouteFare `json:"rideFares"`
+}
+
+// startTripRequest mirrors HTTPTripStartRequestPayload in web/src/contracts.ts
+type startTripRequest struct {
+	RideFareID string `json:"rideFareID"`
+	UserID     string `json:"userID"`
+	PromoCode  string `json:"promoCode,omitempty"`
+	FareToken  string `json:"fareToken"`
+}
+
+// toProto converts the HTTP payload into a CreateTripRequest
+func (r *startTripRequest) toProto() *pb.CreateTripRequest {
+	return &pb.CreateTripRequest{
+		UserId:    r.UserID,
+		FareId:    r.RideFareID,
+		PromoCode: r.PromoCode,
+		FareToken: r.FareToken,
+	}
+}
+
+// startTripResponse mirrors HTTPTripStartResponse in web/src/types.ts
+type startTripResponse struct {
+	TripID string `json:"tripID"`
+}
+
+// cancelTripRequest is the payload sent to /trip/{id}/cancel
+type cancelTripRequest struct {
+	UserID      string `json:"userID"`
+	CancelledBy string `json:"cancelledBy"` // "rider" or "driver"
+	Reason      string `json:"reason"`
+}
+
+// toProto converts the HTTP payload into a CancelTripRequest
+func (r *cancelTripRequest) toProto(tripID string) *pb.CancelTripRequest {
+	cancelledBy := pb.TripActor_TRIP_ACTOR_UNSPECIFIED
+	switch r.CancelledBy {
+	case "rider":
+		cancelledBy = pb.TripActor_TRIP_ACTOR_RIDER
+	case "driver":
+		cancelledBy = pb.TripActor_TRIP_ACTOR_DRIVER
+	}
+
+	return &pb.CancelTripRequest{
+		TripId:      tripID,
+		UserId:      r.UserID,
+		CancelledBy: cancelledBy,
+		Reason:      r.Reason,
+	}
+}
+
+// cancelTripResponse is returned by /trip/{id}/cancel
+type cancelTripResponse struct {
+	TripID                 string `json:"tripID"`
+	Status                 string `json:"status"`
+	CancellationFeeInCents int64  `json:"cancellationFeeInCents"`
+}
+
+func newPreviewTripResponse(resp *pb.PreviewTripResponse) *previewTripResponse {
+	fares := make([]*types.RouteFare, 0, len(resp.GetRideFares()))
+	for _, fare := range resp.GetRideFares() {
+		fares = append(fares, routeFareFromProto(fare))
+	}
+
+	return &previewTripResponse{
+		Route:     routeFromProto(resp.GetRoute()),
+		RideFares: fares,
+	}
+}
+
+func routeFromProto(route *pb.Route) *types.Route {
+	if route == nil {
+		return nil
+	}
+
+	geometry := make([]*types.Geometry, 0, len(route.GetGeometry()))
+	for _, g := range route.GetGeometry() {
+		coordinates := make([]*types.Coordinate, 0, len(g.GetCoordinates()))
+		for _, c := range g.GetCoordinates() {
+			coordinates = append(coordinates, &types.Coordinate{
+				Latitude:  c.GetLatitude(),
+				Longitude: c.GetLongitude(),
+			})
+		}
+		geometry = append(geometry, &types.Geometry{Coordinates: coordinates})
+	}
+
+	legs := make([]*types.RouteLeg, 0, len(route.GetLegs()))
+	for _, leg := range route.GetLegs() {
+		legs = append(legs, &types.RouteLeg{Distance: leg.GetDistance(), Duration: leg.GetDuration()})
+	}
+
+	return &types.Route{
+		Distance: route.GetDistance(),
+		Duration: route.GetDuration(),
+		Geometry: geometry,
+		Legs:     legs,
+	}
+}
+
+func routeFareFromProto(fare *pb.RouteFare) *types.RouteFare {
+	return &types.RouteFare{
+		ID:                fare.GetId(),
+		PackageSlug:       fare.GetPackageSlug(),
+		BasePrice:         fare.GetBasePrice(),
+		TotalPriceInCents: fare.GetTotalPriceInCents(),
+		ExpiresAt:         time.Unix(fare.GetExpiresAt(), 0).UTC(),
+		Route:             routeFromProto(fare.GetRoute()),
+		Breakdown:         fareLineItemsFromProto(fare.GetBreakdown()),
+		PricingRegion:     fare.GetPricingRegion(),
+		PricingVersion:    fare.GetPricingVersion(),
+		SurgeMultiplier:   fare.GetSurgeMultiplier(),
+		PromoCode:         fare.GetPromoCode(),
+		DiscountInCents:   fare.GetDiscountInCents(),
+		Token:             fare.GetToken(),
+		PickupAt:          timeFromUnix(fare.GetPickupAt()),
+	}
+}
+
+// timeFromUnix converts an optional Unix timestamp, where 0 means unset
+func timeFromUnix(sec int64) *time.Time {
+	if sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0).UTC()
+	return &t
+}
+
+func fareLineItemsFromProto(items []*pb.FareLineItem) []*types.FareLineItem {
+	result := make([]*types.FareLineItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, &types.FareLineItem{
+			Type:          item.GetType(),
+			AmountInCents: item.GetAmountInCents(),
+		})
+	}
+	return result
+}
+
+// tripStatusFromProto turns TRIP_STATUS_DRIVER_ASSIGNED into driver_assigned, matching the trip JSON
+func tripStatusFromProto(status pb.TripStatus) string {
+	return strings.ToLower(strings.TrimPrefix(status.String(), "TRIP_STATUS_"))
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/util"
+)
+
+var upgrader = websocket.Upgrader{
+	// The frontend runs on a different origin, same as for the HTTP API
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// RidersWSHandler - keeps a websocket open to push trip updates to a rider
+func ridersWSHandler(connections *ConnectionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := r.URL.Query().Get("userID")
+		if userID == "" {
+			http.Error(w, "userID is required", http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("❌ Websocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		c := connections.Add(userID, conn)
+		defer connections.Remove(userID, c)
+
+		log.Printf("🔌 Rider %s connected", userID)
+
+		// Riders only listen, but we still need to read to notice the connection closing
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				log.Printf("🔌 Rider %s disconnected", userID)
+				return
+			}
+		}
+	}
+}
+
+// DriversWSHandler - keeps a websocket open to push trip requests to a driver and relay their commands
+func driversWSHandler(connections *ConnectionManager, bus *EventBus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := r.URL.Query().Get("userID")
+		packageSlug := r.URL.Query().Get("packageSlug")
+		if userID == "" {
+			http.Error(w, "userID is required", http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("❌ Websocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		c := connections.Add(userID, conn)
+		defer connections.Remove(userID, c)
+
+		log.Printf("🔌 Driver %s connected", userID)
+
+		driver := newDriver(userID, tripTypes.CarPackageSlug(packageSlug))
+
+		registration := contracts.DriverCmdRegisterData{DriverPayload: contracts.DriverPayload{Driver: driver}}
+		if err := messaging.Publish(r.Context(), bus, contracts.DriverCmdRegisterTopic, driver.ID, registration); err != nil {
+			log.Printf("❌ Failed to register driver %s: %v", userID, err)
+			return
+		}
+
+		if err := c.WriteJSON(contracts.WSMessage{Type: contracts.DriverCmdRegister, Data: driver}); err != nil {
+			log.Printf("❌ Failed to send driver registration: %v", err)
+			return
+		}
+
+		for {
+			var msg contracts.WSDriverMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				log.Printf("🔌 Driver %s disconnected", userID)
+				return
+			}
+
+			if err := handleDriverMessage(r, bus, driver, msg); err != nil {
+				log.Printf("❌ Failed to handle %s from driver %s: %v", msg.Type, userID, err)
+			}
+		}
+	}
+}
+
+// handleDriverMessage relays a websocket command from a driver to the trip exchange
+func handleDriverMessage(r *http.Request, bus *EventBus, driver *tripTypes.Driver, msg contracts.WSDriverMessage) error {
+	switch msg.Type {
+	case contracts.DriverCmdLocation:
+		var data struct {
+			Location *tripTypes.Coordinate `json:"location"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return err
+		}
+
+		driver.Location = data.Location
+		location := contracts.DriverCmdLocationData{DriverPayload: contracts.DriverPayload{Driver: driver}}
+		return messaging.Publish(r.Context(), bus, contracts.DriverCmdLocationTopic, driver.ID, location)
+
+	case contracts.DriverCmdTripAccept, contracts.DriverCmdTripDecline:
+		var data struct {
+			TripID  string `json:"tripID"`
+			RiderID string `json:"riderID"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return err
+		}
+
+		response := contracts.DriverTripResponse{
+			TripID:   data.TripID,
+			RiderID:  data.RiderID,
+			DriverID: driver.ID,
+			Accepted: msg.Type == contracts.DriverCmdTripAccept,
+		}
+		if response.Accepted {
+			return messaging.Publish(r.Context(), bus, contracts.DriverCmdTripAcceptTopic, driver.ID, contracts.DriverCmdTripAcceptData{DriverTripResponse: response})
+		}
+		return messaging.Publish(r.Context(), bus, contracts.DriverCmdTripDeclineTopic, driver.ID, contracts.DriverCmdTripDeclineData{DriverTripResponse: response})
+
+	case contracts.DriverCmdTripStart, contracts.DriverCmdTripStopReached, contracts.DriverCmdTripComplete:
+		var data struct {
+			TripID    string `json:"tripID"`
+			StopIndex int    `json:"stopIndex"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return err
+		}
+
+		command := contracts.DriverTripCommand{
+			TripID:    data.TripID,
+			DriverID:  driver.ID,
+			Location:  driver.Location,
+			StopIndex: data.StopIndex,
+		}
+		switch msg.Type {
+		case contracts.DriverCmdTripStart:
+			return messaging.Publish(r.Context(), bus, contracts.DriverCmdTripStartTopic, driver.ID, contracts.DriverCmdTripStartData{DriverTripCommand: command})
+		case contracts.DriverCmdTripStopReached:
+			return messaging.Publish(r.Context(), bus, contracts.DriverCmdTripStopReachedTopic, driver.ID, contracts.DriverCmdTripStopReachedData{DriverTripCommand: command})
+		default:
+			return messaging.Publish(r.Context(), bus, contracts.DriverCmdTripCompleteTopic, driver.ID, contracts.DriverCmdTripCompleteData{DriverTripCommand: command})
+		}
+
+	default:
+		return fmt.Errorf("unknown message type %q", msg.Type)
+	}
+}
+
+// newDriver builds the profile shown to riders for a newly connected driver
+func newDriver(userID string, packageSlug tripTypes.CarPackageSlug) *tripTypes.Driver {
+	return &tripTypes.Driver{
+		ID:             userID,
+		Name:           "Driver " + userID[:min(len(userID), 6)],
+		ProfilePicture: util.GetRandomAvatar(rand.Intn(9) + 1),
+		CarPlate:       fmt.Sprintf("%03d-%c%c%c", rand.Intn(1000), 'A'+rand.Intn(26), 'A'+rand.Intn(26), 'A'+rand.Intn(26)),
+		PackageSlug:    packageSlug,
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrFareNotFound is returned when a fare ID does not match any quoted fare
+	ErrFareNotFound = errors.New("fare not found")
+
+	// ErrFareExpired is returned when a quoted fare is used after its expiry
+	ErrFareExpired = errors.New("fare has expired")
+
+	// ErrFareNotOwned is returned when a user tries to book a fare quoted for someone else
+	ErrFareNotOwned = errors.New("fare does not belong to user")
+
+	// ErrTripNotFound is returned when a trip ID does not match any trip
+	ErrTripNotFound = errors.New("trip not found")
+
+	// ErrInvalidTransition is matched by every InvalidTransitionError
+	ErrInvalidTransition = errors.New("invalid trip status transition")
+
+	// ErrStatusConflict is returned when a trip's status changed between reading and writing it
+	ErrStatusConflict = errors.New("trip status changed concurrently")
+
+	// ErrDriverNotOffered is returned when a driver answers a trip that is not currently offered to them
+	ErrDriverNotOffered = errors.New("trip is not offered to driver")
+
+	// ErrOfferExpired is returned when a driver answers a trip offer after its deadline
+	ErrOfferExpired = errors.New("trip offer has expired")
+
+	// ErrNotTripParticipant is returned when someone other than the trip's rider or driver acts on it
+	ErrNotTripParticipant = errors.New("user is not a participant of the trip")
+
+	// ErrDriverNotAtPickup is returned when a driver tries to start a trip away from the pickup
+	ErrDriverNotAtPickup = errors.New("driver is not at the pickup location")
+
+	// ErrPromoNotFound is returned when a promo code does not exist
+	ErrPromoNotFound = errors.New("promo code not found")
+
+	// ErrPromoNotApplicable is returned when a promo code exists but cannot be used for the ride
+	ErrPromoNotApplicable = errors.New("promo code cannot be applied")
+
+	// ErrPromoLimitReached is returned when a promo code has been redeemed as often as allowed
+	ErrPromoLimitReached = errors.New("promo code redemption limit reached")
+
+	// ErrFareTokenInvalid is returned when a fare token is malformed, tampered with or used for another fare
+	ErrFareTokenInvalid = errors.New("invalid fare token")
+
+	// ErrFareAlreadyBooked is returned when a quoted fare is used for a second trip
+	ErrFareAlreadyBooked = errors.New("fare has already been booked")
+
+	// ErrPricingVersionNotFound is returned when a fare refers to a pricing table that no longer exists
+	ErrPricingVersionNotFound = errors.New("pricing version not found")
+
+	// ErrInvalidStop is returned when a stop does not exist, was already reached or is not the next one
+	ErrInvalidStop = errors.New("invalid trip stop")
+
+	// ErrInvalidPickupTime is returned when a scheduled pickup is too soon or too far ahead
+	ErrInvalidPickupTime = errors.New("invalid scheduled pickup time")
+)
@@ -2,56 +2,260 @@ package domain
 
 import (
 	"context"
+	"time"
+
 	"ride-sharing/services/trip-service/pkg/types"
 )
 
 // TripRepository defines the interface for trip data persistence
 type TripRepository interface {
-	// Create creates a new trip in the database
+	// Create creates a new trip in the database.
+	// It returns ErrFareAlreadyBooked when another trip was created with the same fare.
 	Create(ctx context.Context, trip *types.Trip) error
-	
+
 	// GetByID retrieves a trip by its ID
 	GetByID(ctx context.Context, id string) (*types.Trip, error)
-	
-	// Update updates an existing trip
-	Update(ctx context.Context, trip *types.Trip) error
-	
-	// UpdateStatus updates only the status of a trip
-	UpdateStatus(ctx context.Context, id string, status types.TripStatus) error
+
+	// Update updates an existing trip if it is still in the expected status and, for pool trips,
+	// still has the same riders. It returns ErrStatusConflict when another writer got there first.
+	Update(ctx context.Context, trip *types.Trip, expected types.TripStatus) error
+
+	// AddPoolRider stores a pool trip the last rider was just added to, if no other rider joined meanwhile.
+	// It returns ErrStatusConflict when the trip changed, and ErrFareAlreadyBooked when the rider's fare was booked before.
+	AddPoolRider(ctx context.Context, trip *types.Trip, expected types.TripStatus) error
+
+	// FindPoolTrips returns the most recent pool trips in one of the given statuses
+	FindPoolTrips(ctx context.Context, statuses []types.TripStatus, limit int) ([]*types.Trip, error)
+
+	// UpdateStatus moves a trip from one status to another using compare-and-set
+	UpdateStatus(ctx context.Context, id string, from, to types.TripStatus) error
+
+	// FindExpiredOffers returns trips whose outstanding driver offer lapsed before the given time
+	FindExpiredOffers(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error)
+
+	// FindUndispatched returns trips still waiting for a driver offer that were last updated before the given time
+	FindUndispatched(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error)
+
+	// HasTrips reports whether a user ever booked a trip that was not cancelled, alone or in a pool
+	HasTrips(ctx context.Context, userID string) (bool, error)
+
+	// FindDueScheduled returns scheduled trips whose dispatch time is before the given time
+	FindDueScheduled(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error)
+
+	// FindDueReminders returns scheduled trips whose reminder is due before the given time and not sent yet
+	FindDueReminders(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error)
+
+	// MarkReminderSent records that a trip's reminder was sent.
+	// It reports false when the reminder had already been claimed by someone else.
+	MarkReminderSent(ctx context.Context, id string, at time.Time) (bool, error)
+
+	// AppendTrace records a location of a driver on the trip they are driving, if any
+	AppendTrace(ctx context.Context, driverID string, point *types.TracePoint) error
 }
 
-// EventPublisher defines the interface for publishing events to RabbitMQ
+// Transactor defines the interface for running repository calls atomically
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction that repository calls made with the context passed to fn
+	// take part in. Everything is rolled back when fn returns an error. Nested calls join the outer transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// OutboxRepository defines the interface for events waiting to be published
+type OutboxRepository interface {
+	// Add stores an event, as part of the transaction of the context if there is one
+	Add(ctx context.Context, msg *types.OutboxMessage) error
+
+	// ClaimPending locks up to limit unsent messages, oldest first, so no other relay publishes them until the lease ends.
+	// Messages of a trip with an older message still locked or left unclaimed are skipped, keeping each trip's order.
+	ClaimPending(ctx context.Context, lease time.Duration, limit int) ([]*types.OutboxMessage, error)
+
+	// Release ends the lease of claimed messages that were not published, so they are claimed again right away
+	Release(ctx context.Context, ids []string) error
+
+	// MarkSent records that a message was confirmed by the broker
+	MarkSent(ctx context.Context, id string, at time.Time) error
+
+	// MarkFailed records that a message will never be published, and why
+	MarkFailed(ctx context.Context, id string, at time.Time, reason string) error
+}
+
+// ProcessedMessageRepository defines the interface for the messages consumers already handled
+type ProcessedMessageRepository interface {
+	// IsProcessed reports whether a consumer already handled the message with the given ID
+	IsProcessed(ctx context.Context, consumer, messageID string) (bool, error)
+
+	// MarkProcessed records that a consumer handled the message with the given ID
+	MarkProcessed(ctx context.Context, consumer, messageID string, at time.Time) error
+}
+
+// PromotionRepository defines the interface for promo codes and their redemptions
+type PromotionRepository interface {
+	// GetByCode retrieves a promotion by its code
+	GetByCode(ctx context.Context, code string) (*types.Promotion, error)
+
+	// CountUserRedemptions returns how often a user redeemed a promo code
+	CountUserRedemptions(ctx context.Context, code, userID string) (int64, error)
+
+	// Redeem records a redemption, atomically enforcing the per-user and global limits.
+	// It returns ErrPromoLimitReached when either limit is exhausted, and ErrPromoNotApplicable when
+	// the user already redeemed a first ride promotion. Call it in the transaction that books the trip,
+	// so the redemption is undone when the booking fails.
+	Redeem(ctx context.Context, promo *types.Promotion, redemption *types.PromoRedemption) error
+}
+
+// RideFareRepository defines the interface for persisting quoted fares
+type RideFareRepository interface {
+	// SaveMany stores the fares quoted by a trip preview
+	SaveMany(ctx context.Context, fares []*types.RouteFare) error
+
+	// GetByID retrieves a quoted fare by its ID
+	GetByID(ctx context.Context, id string) (*types.RouteFare, error)
+}
+
+// DriverRepository defines the interface for tracking driver locations and availability
+type DriverRepository interface {
+	// Upsert stores the latest known state of a driver
+	Upsert(ctx context.Context, driver *types.Driver) error
+
+	// GetByID retrieves a driver by its ID
+	GetByID(ctx context.Context, id string) (*types.Driver, error)
+
+	// FindCandidates returns available drivers near the pickup, closest first,
+	// skipping the excluded driver IDs
+	FindCandidates(ctx context.Context, pickup *types.Coordinate, packageSlug types.CarPackageSlug, exclude []string, limit int) ([]*types.Driver, error)
+
+	// SetAvailable marks whether a driver can receive new trip offers
+	SetAvailable(ctx context.Context, driverID string, available bool) error
+
+	// CountAvailable returns how many available, recently seen drivers are near the pickup,
+	// counting the drivers FindCandidates would search
+	CountAvailable(ctx context.Context, pickup *types.Coordinate) (int64, error)
+}
+
+// SurgeRepository defines the interface for tracking rider demand per geohash cell
+type SurgeRepository interface {
+	// RecordDemand notes that a rider is looking for a ride in a cell
+	RecordDemand(ctx context.Context, cell, userID string) error
+
+	// CountDemand returns how many distinct riders looked for a ride in a cell since the given time
+	CountDemand(ctx context.Context, cell string, since time.Time) (int64, error)
+}
+
+// SurgePricer defines the interface for demand based price multipliers
+type SurgePricer interface {
+	// RecordDemand notes that a rider is looking for a ride from the pickup
+	RecordDemand(ctx context.Context, userID string, pickup *types.Coordinate) error
+
+	// Multiplier returns the surge multiplier for the cell of the pickup, 1 when there is no surge
+	Multiplier(ctx context.Context, pickup *types.Coordinate) (float64, error)
+}
+
+// EventPublisher defines the interface for publishing events to RabbitMQ.
+// Events are written to the outbox, so publishing within a transaction makes them part of it.
 type EventPublisher interface {
 	// PublishTripCreated publishes a trip.event.created event
 	PublishTripCreated(ctx context.Context, trip *types.Trip) error
-	
+
+	// PublishTripScheduled publishes a trip.event.scheduled event
+	PublishTripScheduled(ctx context.Context, trip *types.Trip) error
+
+	// PublishScheduledReminder publishes a trip.event.scheduled_reminder event
+	PublishScheduledReminder(ctx context.Context, trip *types.Trip) error
+
 	// PublishDriverAssigned publishes a trip.event.driver_assigned event
 	PublishDriverAssigned(ctx context.Context, trip *types.Trip) error
-	
+
 	// PublishNoDriversFound publishes a trip.event.no_drivers_found event
-	PublishNoDriversFound(ctx context.Context, tripID string) error
+	PublishNoDriversFound(ctx context.Context, trip *types.Trip) error
+
+	// PublishPoolRiderJoined publishes a trip.event.pool_rider_joined event
+	PublishPoolRiderJoined(ctx context.Context, trip *types.Trip) error
+
+	// PublishPoolRiderLeft publishes a trip.event.pool_rider_left event
+	PublishPoolRiderLeft(ctx context.Context, trip *types.Trip) error
+
+	// PublishTripCancelled publishes a trip.event.cancelled event
+	PublishTripCancelled(ctx context.Context, trip *types.Trip) error
+
+	// PublishTripStarted publishes a trip.event.started event
+	PublishTripStarted(ctx context.Context, trip *types.Trip) error
+
+	// PublishStopReached publishes a trip.event.stop_reached event
+	PublishStopReached(ctx context.Context, trip *types.Trip) error
+
+	// PublishTripCompleted publishes a trip.event.completed event
+	PublishTripCompleted(ctx context.Context, trip *types.Trip) error
+
+	// PublishDriverTripRequest publishes a driver.cmd.trip_request offering the trip to a driver
+	PublishDriverTripRequest(ctx context.Context, trip *types.Trip, offer *types.DispatchAttempt) error
+
+	// PublishDriverNotInterested publishes a trip.event.driver_not_interested event
+	PublishDriverNotInterested(ctx context.Context, trip *types.Trip, driverID string) error
 }
 
 // OSRMClient defines the interface for OSRM routing API
 type OSRMClient interface {
-	// GetRoute calculates a route between two coordinates
-	GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*types.Route, error)
+	// GetRoute calculates a route from pickup to destination through the waypoints, in order
+	GetRoute(ctx context.Context, pickup, destination *types.Coordinate, waypoints []*types.Coordinate) (*types.Route, error)
+}
+
+// PricingRepository defines the interface for loading versioned pricing tables
+type PricingRepository interface {
+	// ListLatest returns the newest pricing table of every region
+	ListLatest(ctx context.Context) ([]*types.PricingTable, error)
+
+	// GetVersion returns a specific pricing table version of a region
+	GetVersion(ctx context.Context, region string, version int64) (*types.PricingTable, error)
 }
 
 // FareCalculator defines the interface for calculating trip fares
 type FareCalculator interface {
-	// CalculateFares calculates fare options for a given route
-	CalculateFares(ctx context.Context, route *types.Route) ([]*types.RouteFare, error)
+	// CalculateFares calculates fare options for a given route, priced for the region of the pickup
+	CalculateFares(ctx context.Context, route *types.Route, pickup *types.Coordinate) ([]*types.RouteFare, error)
+
+	// RepriceFare prices a route with the pricing version and surge a fare was quoted with
+	RepriceFare(ctx context.Context, fare *types.RouteFare, route *types.Route) ([]*types.FareLineItem, error)
 }
 
 // TripService defines the business logic interface for trip operations
 type TripService interface {
-	// PreviewTrip calculates route and fare options without creating a trip
-	PreviewTrip(ctx context.Context, userID string, pickup, destination *types.Coordinate) (*types.Route, []*types.RouteFare, error)
-	
-	// CreateTrip creates a new trip with the selected fare
-	CreateTrip(ctx context.Context, userID string, fareID string, pickup, destination *types.Coordinate) (*types.Trip, error)
-	
+	// PreviewTrip calculates route and fare options without creating a trip, stopping at the
+	// waypoints in order and discounted by the promo code if one is given.
+	// A pickup time books the ride ahead, and the quotes then stay valid for longer.
+	PreviewTrip(ctx context.Context, userID string, pickup, destination *types.Coordinate, waypoints []*types.Coordinate, pickupAt *time.Time, promoCode string) (*types.Route, []*types.RouteFare, error)
+
+	// CreateTrip creates a new trip with the fare vouched for by the fare token,
+	// redeeming the promo code it was quoted with. A different promo code is rejected.
+	// Pool fares join a pool trip going the same way when one can take the rider.
+	CreateTrip(ctx context.Context, userID string, fareID string, fareToken string, promoCode string) (*types.Trip, error)
+
+	// CancelTrip cancels a trip on behalf of its rider or assigned driver and returns the cancellation made.
+	// A rider leaving a pool trip others still share only removes themselves from it, so the cancellation
+	// is theirs and the trip goes on.
+	CancelTrip(ctx context.Context, tripID string, actorID string, actor types.TripActor, reason string) (*types.Trip, *types.Cancellation, error)
+
+	// StartTrip marks the rider as picked up by the assigned driver
+	StartTrip(ctx context.Context, tripID string, driverID string, location *types.Coordinate) (*types.Trip, error)
+
+	// MarkStopReached records that the assigned driver reached an intermediate stop.
+	// Stops must be reached in order.
+	MarkStopReached(ctx context.Context, tripID string, driverID string, stopIndex int) (*types.Trip, error)
+
+	// CompleteTrip marks the trip as finished by the assigned driver
+	CompleteTrip(ctx context.Context, tripID string, driverID string) (*types.Trip, error)
+
 	// HandleDriverResponse processes a driver's accept/decline response
 	HandleDriverResponse(ctx context.Context, tripID string, driverID string, accepted bool) error
+
+	// ProcessScheduledTrips sends due reminders and starts dispatching scheduled trips whose dispatch time has come
+	ProcessScheduledTrips(ctx context.Context) error
+
+	// ExpireOffers treats every lapsed driver offer as a decline and dispatches the trip again
+	ExpireOffers(ctx context.Context) error
+
+	// RedispatchStalled dispatches the trips left waiting for a driver offer after a failed dispatch
+	RedispatchStalled(ctx context.Context) error
+
+	// UpdateDriverLocation records a driver's latest location so it can be offered trips
+	UpdateDriverLocation(ctx context.Context, driver *types.Driver) error
 }
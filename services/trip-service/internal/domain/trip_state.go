@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// tripTransitions lists, for every status, the statuses a trip may move to next.
+// Completed and cancelled are terminal.
+var tripTransitions = map[types.TripStatus][]types.TripStatus{
+	types.TripStatusPending: {
+		types.TripStatusCreated,
+		types.TripStatusCancelled,
+	},
+	types.TripStatusScheduled: {
+		types.TripStatusCreated, // dispatch starts ahead of the pickup time
+		types.TripStatusCancelled,
+	},
+	types.TripStatusCreated: {
+		types.TripStatusDriverFound,
+		types.TripStatusDriverAssigned, // a driver accepted straight away
+		types.TripStatusCancelled,
+	},
+	types.TripStatusDriverFound: {
+		types.TripStatusDriverAssigned,
+		types.TripStatusCreated, // the offered driver declined, search again
+		types.TripStatusCancelled,
+	},
+	types.TripStatusDriverAssigned: {
+		types.TripStatusInProgress,
+		types.TripStatusCancelled,
+	},
+	types.TripStatusInProgress: {
+		types.TripStatusCompleted,
+		types.TripStatusCancelled,
+	},
+	types.TripStatusCompleted: {},
+	types.TripStatusCancelled: {},
+}
+
+// InvalidTransitionError is returned when a trip cannot move from one status to another
+type InvalidTransitionError struct {
+	From types.TripStatus
+	To   types.TripStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("%v: %s -> %s", ErrInvalidTransition, e.From, e.To)
+}
+
+// Is lets callers match any InvalidTransitionError with errors.Is(err, ErrInvalidTransition)
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// CanTransition reports whether a trip may move from one status to another
+func CanTransition(from, to types.TripStatus) bool {
+	for _, next := range tripTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an InvalidTransitionError if the status change is not allowed
+func ValidateTransition(from, to types.TripStatus) error {
+	if !CanTransition(from, to) {
+		return &InvalidTransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// IsTerminal reports whether no further transitions are possible from the status
+func IsTerminal(status types.TripStatus) bool {
+	next, ok := tripTransitions[status]
+	return ok && len(next) == 0
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+var allStatuses = []types.TripStatus{
+	types.TripStatusPending,
+	types.TripStatusScheduled,
+	types.TripStatusCreated,
+	types.TripStatusDriverFound,
+	types.TripStatusDriverAssigned,
+	types.TripStatusInProgress,
+	types.TripStatusCompleted,
+	types.TripStatusCancelled,
+}
+
+func TestValidateTransition(t *testing.T) {
+	// Written out rather than read from tripTransitions, so a change to the table shows up here
+	allowed := map[types.TripStatus][]types.TripStatus{
+		types.TripStatusPending:        {types.TripStatusCreated, types.TripStatusCancelled},
+		types.TripStatusScheduled:      {types.TripStatusCreated, types.TripStatusCancelled},
+		types.TripStatusCreated:        {types.TripStatusDriverFound, types.TripStatusDriverAssigned, types.TripStatusCancelled},
+		types.TripStatusDriverFound:    {types.TripStatusDriverAssigned, types.TripStatusCreated, types.TripStatusCancelled},
+		types.TripStatusDriverAssigned: {types.TripStatusInProgress, types.TripStatusCancelled},
+		types.TripStatusInProgress:     {types.TripStatusCompleted, types.TripStatusCancelled},
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			want := false
+			for _, next := range allowed[from] {
+				want = want || next == to
+			}
+
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				if got := CanTransition(from, to); got != want {
+					t.Errorf("CanTransition = %v, want %v", got, want)
+				}
+
+				err := ValidateTransition(from, to)
+				if want {
+					if err != nil {
+						t.Errorf("ValidateTransition = %v, want nil", err)
+					}
+					return
+				}
+
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("ValidateTransition = %v, want ErrInvalidTransition", err)
+				}
+				var transitionErr *InvalidTransitionError
+				if !errors.As(err, &transitionErr) || transitionErr.From != from || transitionErr.To != to {
+					t.Errorf("ValidateTransition = %#v, want InvalidTransitionError{%s, %s}", err, from, to)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateTransitionUnknownStatus(t *testing.T) {
+	if err := ValidateTransition("unknown", types.TripStatusCreated); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("ValidateTransition from unknown status = %v, want ErrInvalidTransition", err)
+	}
+	if err := ValidateTransition(types.TripStatusCreated, "unknown"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("ValidateTransition to unknown status = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	for _, status := range allStatuses {
+		want := status == types.TripStatusCompleted || status == types.TripStatusCancelled
+		if got := IsTerminal(status); got != want {
+			t.Errorf("IsTerminal(%s) = %v, want %v", status, got, want)
+		}
+	}
+
+	if IsTerminal("unknown") {
+		t.Error("IsTerminal(unknown) = true, want false")
+	}
+}
+
+func TestTransitionsCoverEveryStatus(t *testing.T) {
+	for _, status := range allStatuses {
+		if _, ok := tripTransitions[status]; !ok {
+			t.Errorf("tripTransitions has no entry for %s", status)
+		}
+	}
+}
@@ -2,137 +2,254 @@ package events
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 
 	amqp "github.com/rabbitmq/amqp091-go"
-	"ride-sharing/services/trip-service/internal/service"
+	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+)
+
+const (
+	// driverResponseQueue is the queue driver responses to trip offers are consumed from
+	driverResponseQueue = "driver_trip_response"
+
+	// driverTripLifecycleQueue is the queue driver trip start, stop reached and complete commands are consumed from
+	driverTripLifecycleQueue = "driver_trip_lifecycle"
 )
 
 // EventConsumer handles consuming events from RabbitMQ
 type EventConsumer struct {
-	channel  *amqp.Channel
-	service  *service.TripServiceImpl
+	conn      *messaging.Connection
+	service   domain.TripService
+	processed domain.ProcessedMessageRepository
+	cfg       ConsumerConfig
+	running   sync.WaitGroup
 }
 
-// NewEventConsumer creates a new event consumer
-func NewEventConsumer(ch *amqp.Channel, tripService *service.TripServiceImpl) (*EventConsumer, error) {
+// NewEventConsumer creates a new event consumer.
+// Driver commands already handled are skipped using processed, and failed driver responses and trip
+// commands are retried with the backoff of cfg.Retry, then dead-lettered.
+func NewEventConsumer(conn *messaging.Connection, tripService domain.TripService, processed domain.ProcessedMessageRepository, cfg ConsumerConfig) (*EventConsumer, error) {
 	// Declare trip exchange
-	err := ch.ExchangeDeclare(
-		"trip_exchange", // name
-		"topic",         // type
-		true,            // durable
-		false,           // auto-deleted
-		false,           // internal
-		false,           // no-wait
-		nil,             // arguments
-	)
+	err := conn.DeclareExchange(messaging.Exchange{Name: "trip_exchange", Kind: "topic"})
 	if err != nil {
-		return nil, fmt.Errorf("failed to declare trip exchange: %w", err)
+		return nil, err
 	}
 
 	return &EventConsumer{
-		channel: ch,
-		service: tripService,
+		conn:      conn,
+		service:   tripService,
+		processed: processed,
+		cfg:       cfg,
 	}, nil
 }
 
 // StartDriverResponseConsumer starts consuming driver response messages
 func (c *EventConsumer) StartDriverResponseConsumer(ctx context.Context) error {
-	// Declare queue
-	queue, err := c.channel.QueueDeclare(
-		"driver_trip_response", // name
-		true,                    // durable
-		false,                   // delete when unused
-		false,                   // exclusive
-		false,                   // no-wait
-		nil,                      // arguments
-	)
+	args, err := c.declareRetries(driverResponseQueue)
 	if err != nil {
-		return fmt.Errorf("failed to declare queue: %w", err)
+		return err
 	}
 
-	// Bind queue to exchange
-	err = c.channel.QueueBind(
-		queue.Name,              // queue name
-		contracts.DriverCmdTripAccept, // routing key (also handles decline)
-		"trip_exchange",         // exchange
-		false,
-		nil,
-	)
+	router := messaging.NewRouter()
+	messaging.Subscribe(router, contracts.DriverCmdTripAcceptTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdTripAcceptData) error {
+		return c.driverResponded(ctx, data.DriverTripResponse)
+	})
+	messaging.Subscribe(router, contracts.DriverCmdTripDeclineTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdTripDeclineData) error {
+		return c.driverResponded(ctx, data.DriverTripResponse)
+	})
+
+	err = c.consume(ctx, driverResponseQueue, args, router.Keys(), tripKey,
+		Deduplicate(c.processed, driverResponseQueue, c.handleDriverResponse(router)))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Started driver response consumer")
+	return nil
+}
+
+// StartDriverTripLifecycleConsumer starts consuming driver trip start, stop reached and complete commands
+func (c *EventConsumer) StartDriverTripLifecycleConsumer(ctx context.Context) error {
+	args, err := c.declareRetries(driverTripLifecycleQueue)
 	if err != nil {
-		return fmt.Errorf("failed to bind queue: %w", err)
+		return err
 	}
 
-	// Also bind for decline
-	err = c.channel.QueueBind(
-		queue.Name,
-		contracts.DriverCmdTripDecline,
-		"trip_exchange",
-		false,
-		nil,
-	)
+	router := messaging.NewRouter()
+	messaging.Subscribe(router, contracts.DriverCmdTripStartTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdTripStartData) error {
+		logDriverTripCommand(contracts.DriverCmdTripStart, data.DriverTripCommand)
+		_, err := c.service.StartTrip(ctx, data.TripID, data.DriverID, data.Location)
+		return err
+	})
+	messaging.Subscribe(router, contracts.DriverCmdTripStopReachedTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdTripStopReachedData) error {
+		logDriverTripCommand(contracts.DriverCmdTripStopReached, data.DriverTripCommand)
+		_, err := c.service.MarkStopReached(ctx, data.TripID, data.DriverID, data.StopIndex)
+		return err
+	})
+	messaging.Subscribe(router, contracts.DriverCmdTripCompleteTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdTripCompleteData) error {
+		logDriverTripCommand(contracts.DriverCmdTripComplete, data.DriverTripCommand)
+		_, err := c.service.CompleteTrip(ctx, data.TripID, data.DriverID)
+		return err
+	})
+
+	err = c.consume(ctx, driverTripLifecycleQueue, args, router.Keys(), tripKey,
+		Deduplicate(c.processed, driverTripLifecycleQueue, c.handleDriverTripCommand(router)))
 	if err != nil {
-		return fmt.Errorf("failed to bind queue for decline: %w", err)
+		return err
 	}
 
-	msgs, err := c.channel.Consume(
-		queue.Name, // queue
-		"",         // consumer
-		false,      // auto-ack
-		false,      // exclusive
-		false,      // no-local
-		false,      // no-wait
-		nil,        // args
-	)
+	log.Println("Started driver trip lifecycle consumer")
+	return nil
+}
+
+// StartDriverLocationConsumer starts consuming driver location and registration messages
+func (c *EventConsumer) StartDriverLocationConsumer(ctx context.Context) error {
+	router := messaging.NewRouter()
+	messaging.Subscribe(router, contracts.DriverCmdLocationTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdLocationData) error {
+		return c.updateDriverLocation(ctx, data.DriverPayload)
+	})
+	messaging.Subscribe(router, contracts.DriverCmdRegisterTopic, func(ctx context.Context, _ *contracts.AmqpMessage, data contracts.DriverCmdRegisterData) error {
+		return c.updateDriverLocation(ctx, data.DriverPayload)
+	})
+
+	err := c.consume(ctx, "trip_driver_location", nil, router.Keys(), driverKey, c.handleDriverLocation(router))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Started driver location consumer")
+	return nil
+}
+
+// consume declares a durable queue with the given arguments, bound to the routing keys, and dispatches its
+// deliveries to handler on the consumer's workers, partitioned by key.
+// Once ctx is cancelled it stops fetching and finishes the deliveries it already received.
+func (c *EventConsumer) consume(ctx context.Context, queueName string, args amqp.Table, routingKeys []string, key partitionKey, handler Handler) error {
+	queue := messaging.Queue{Name: queueName, Args: args}
+	for _, routingKey := range routingKeys {
+		queue.Bindings = append(queue.Bindings, messaging.Binding{Exchange: "trip_exchange", RoutingKey: routingKey})
+	}
+
+	// Declare queue and bind it to the exchange, again after every reconnect
+	if err := c.conn.DeclareQueue(queue); err != nil {
+		return err
+	}
+
+	// Subscribed again after every reconnect
+	msgs, err := c.conn.Consume(ctx, queueName, messaging.ConsumeOptions{Prefetch: c.cfg.Prefetch})
 	if err != nil {
 		return fmt.Errorf("failed to register consumer: %w", err)
 	}
 
+	c.running.Add(1)
 	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case msg, ok := <-msgs:
-				if !ok {
-					return
-				}
-				c.handleDriverResponse(ctx, msg)
-			}
-		}
+		defer c.running.Done()
+
+		// In-flight deliveries are finished even when shutting down
+		runWorkers(context.WithoutCancel(ctx), msgs, c.cfg.Workers, c.cfg.Prefetch, key, handler)
 	}()
 
-	log.Println("Started driver response consumer")
 	return nil
 }
 
-// DriverResponseMessage represents the driver response message
-type DriverResponseMessage struct {
-	TripID   string `json:"tripID"`
-	RiderID  string `json:"riderID"`
-	DriverID string `json:"driverID"`
-	Accepted bool   `json:"accepted"`
+// Wait blocks until the consumers have finished their in-flight deliveries, which they do once the
+// context they were started with is cancelled
+func (c *EventConsumer) Wait() {
+	c.running.Wait()
 }
 
-func (c *EventConsumer) handleDriverResponse(ctx context.Context, msg amqp.Delivery) {
-	var response DriverResponseMessage
-	if err := json.Unmarshal(msg.Body, &response); err != nil {
-		log.Printf("Failed to unmarshal driver response: %v", err)
-		msg.Nack(false, false)
-		return
+// handleDriverResponse settles a driver response by the outcome of router. Failures are retried with
+// backoff, then dead-lettered.
+func (c *EventConsumer) handleDriverResponse(router *messaging.Router) Handler {
+	return func(ctx context.Context, msg amqp.Delivery) {
+		err := router.Handle(ctx, msg)
+		switch {
+		case err == nil:
+			msg.Ack(false)
+		case errors.Is(err, contracts.ErrUnsupportedSchema):
+			// Likely published by a newer replica during a rolling deploy, one of which can handle it later
+			log.Printf("Postponing driver response: %v", err)
+			c.retryLater(ctx, driverResponseQueue, msg)
+		case errors.Is(err, messaging.ErrMalformed), errors.Is(err, messaging.ErrNoSubscriber):
+			log.Printf("Failed to unmarshal driver response: %v", err)
+			msg.Nack(false, false) // Straight to the dead-letter queue
+		case isPermanent(err):
+			log.Printf("Discarding driver response: %v", err)
+			msg.Ack(false)
+		default:
+			log.Printf("Failed to handle driver response: %v", err)
+			c.retryLater(ctx, driverResponseQueue, msg)
+		}
 	}
+}
 
+func (c *EventConsumer) driverResponded(ctx context.Context, response contracts.DriverTripResponse) error {
 	log.Printf("Received driver response: tripID=%s, driverID=%s, accepted=%v",
 		response.TripID, response.DriverID, response.Accepted)
 
-	if err := c.service.HandleDriverResponse(ctx, response.TripID, response.DriverID, response.Accepted); err != nil {
-		log.Printf("Failed to handle driver response: %v", err)
-		msg.Nack(false, true) // Requeue on error
-		return
+	return c.service.HandleDriverResponse(ctx, response.TripID, response.DriverID, response.Accepted)
+}
+
+// handleDriverTripCommand settles a driver trip command by the outcome of router. Failures are retried
+// with backoff, then dead-lettered.
+func (c *EventConsumer) handleDriverTripCommand(router *messaging.Router) Handler {
+	return func(ctx context.Context, msg amqp.Delivery) {
+		err := router.Handle(ctx, msg)
+		switch {
+		case err == nil:
+			msg.Ack(false)
+		case errors.Is(err, contracts.ErrUnsupportedSchema):
+			log.Printf("Postponing driver trip command: %v", err)
+			c.retryLater(ctx, driverTripLifecycleQueue, msg)
+		case errors.Is(err, messaging.ErrMalformed), errors.Is(err, messaging.ErrNoSubscriber):
+			log.Printf("Failed to unmarshal driver trip command: %v", err)
+			msg.Nack(false, false) // Straight to the dead-letter queue
+		case isPermanent(err):
+			log.Printf("Discarding driver trip command: %v", err)
+			msg.Ack(false)
+		default:
+			log.Printf("Failed to handle driver trip command: %v", err)
+			c.retryLater(ctx, driverTripLifecycleQueue, msg)
+		}
 	}
+}
 
-	msg.Ack(false)
+func logDriverTripCommand(routingKey string, command contracts.DriverTripCommand) {
+	log.Printf("Received %s: tripID=%s, driverID=%s", routingKey, command.TripID, command.DriverID)
+}
+
+// isPermanent reports whether retrying the message can never succeed, e.g. because the trip has moved on
+func isPermanent(err error) bool {
+	return errors.Is(err, domain.ErrInvalidTransition) ||
+		errors.Is(err, domain.ErrDriverNotOffered) ||
+		errors.Is(err, domain.ErrOfferExpired) ||
+		errors.Is(err, domain.ErrNotTripParticipant) ||
+		errors.Is(err, domain.ErrDriverNotAtPickup) ||
+		errors.Is(err, domain.ErrInvalidStop)
+}
+
+// handleDriverLocation settles a driver location or registration by the outcome of router
+func (c *EventConsumer) handleDriverLocation(router *messaging.Router) Handler {
+	return func(ctx context.Context, msg amqp.Delivery) {
+		if err := router.Handle(ctx, msg); err != nil {
+			// Locations are refreshed constantly, so a lost update is not worth retrying
+			log.Printf("Failed to update driver location: %v", err)
+			msg.Nack(false, false)
+			return
+		}
+
+		msg.Ack(false)
+	}
+}
+
+func (c *EventConsumer) updateDriverLocation(ctx context.Context, driver contracts.DriverPayload) error {
+	if driver.Driver == nil {
+		return fmt.Errorf("%w: no driver", messaging.ErrMalformed)
+	}
+	return c.service.UpdateDriverLocation(ctx, driver.Driver)
 }
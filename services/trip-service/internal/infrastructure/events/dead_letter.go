@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/retry"
+)
+
+const (
+	// DeadLetterExchange receives the messages consumers gave up on, routed by the name of their queue
+	DeadLetterExchange = "trip_dead_letter"
+
+	// RetryCountHeader counts how many times a message has been scheduled for redelivery
+	RetryCountHeader = "x-retry-count"
+)
+
+// DeadLetterQueue returns the name of the queue holding the messages given up on from a queue
+func DeadLetterQueue(queueName string) string {
+	return queueName + ".dlq"
+}
+
+// retryQueue returns the name of the queue that holds messages of a queue for the given delay
+func retryQueue(queueName string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", queueName, delay)
+}
+
+// declareRetries declares the dead-letter queue and the delay queues of a queue, and returns the
+// arguments the queue itself must be declared with so rejected messages end up in its dead-letter queue.
+//
+// Every retry attempt has its own delay queue with a fixed TTL, so a short delay never waits behind a
+// longer one. Expired messages are dead-lettered from there straight back to the queue.
+func (c *EventConsumer) declareRetries(queueName string) (amqp.Table, error) {
+	err := c.conn.DeclareExchange(messaging.Exchange{Name: DeadLetterExchange, Kind: "direct"})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.conn.DeclareQueue(messaging.Queue{
+		Name:     DeadLetterQueue(queueName),
+		Bindings: []messaging.Binding{{Exchange: DeadLetterExchange, RoutingKey: queueName}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; attempt <= c.cfg.Retry.MaxRetries; attempt++ {
+		delay := retry.Backoff(c.cfg.Retry, attempt)
+		err := c.conn.DeclareQueue(messaging.Queue{
+			Name: retryQueue(queueName, delay),
+			Args: amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queueName,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return amqp.Table{
+		"x-dead-letter-exchange":    DeadLetterExchange,
+		"x-dead-letter-routing-key": queueName,
+	}, nil
+}
+
+// retryLater schedules a failed message for redelivery to its queue after a backoff delay.
+// Once it has been retried MaxRetries times it is rejected instead, which parks it in the dead-letter queue.
+func (c *EventConsumer) retryLater(ctx context.Context, queueName string, msg amqp.Delivery) {
+	// Handing the message over to a retry queue does not make it handled
+	msg = unrecorded(msg)
+
+	attempt := RetryCount(msg.Headers) + 1
+	if attempt > c.cfg.Retry.MaxRetries {
+		log.Printf("Dead-lettering message %s from %s after %d retries", msg.MessageId, queueName, attempt-1)
+		msg.Nack(false, false)
+		return
+	}
+
+	headers := amqp.Table{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[RetryCountHeader] = int32(attempt)
+
+	delay := retry.Backoff(c.cfg.Retry, attempt)
+	err := c.conn.Publish(ctx, "", retryQueue(queueName, delay), amqp.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.MessageId,
+		Timestamp:    msg.Timestamp,
+		Body:         msg.Body,
+	})
+	if err != nil {
+		// The message must not be lost, so fall back to an immediate redelivery
+		log.Printf("Failed to schedule retry of message %s from %s: %v", msg.MessageId, queueName, err)
+		msg.Nack(false, true)
+		return
+	}
+
+	log.Printf("Retrying message %s from %s in %v (attempt %d/%d)", msg.MessageId, queueName, delay, attempt, c.cfg.Retry.MaxRetries)
+	msg.Ack(false)
+}
+
+// RetryCount returns how many times a message has been scheduled for redelivery
+func RetryCount(headers amqp.Table) int {
+	switch count := headers[RetryCountHeader].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// Handler handles a delivery and settles it with Ack or Nack
+type Handler func(ctx context.Context, msg amqp.Delivery)
+
+// Deduplicate wraps a handler so that a message it already handled is acknowledged and skipped when it is delivered again.
+// Messages are told apart by their MessageId, which every publisher stamps. A message counts as handled once the
+// handler acknowledges it; rejected messages, and those acknowledged only to be retried later, are not recorded.
+// Messages without an ID are always handled.
+func Deduplicate(processed domain.ProcessedMessageRepository, consumer string, next Handler) Handler {
+	return func(ctx context.Context, msg amqp.Delivery) {
+		if msg.MessageId == "" {
+			next(ctx, msg)
+			return
+		}
+
+		done, err := processed.IsProcessed(ctx, consumer, msg.MessageId)
+		if err != nil {
+			// Better to handle a duplicate than to stall the queue on the dedup store
+			log.Printf("Failed to look up message %s for %s, handling it: %v", msg.MessageId, consumer, err)
+		}
+		if done {
+			log.Printf("Skipping duplicate message %s for %s", msg.MessageId, consumer)
+			msg.Ack(false)
+			return
+		}
+
+		msg.Acknowledger = &processedAcknowledger{
+			Acknowledger: msg.Acknowledger,
+			record: func() error {
+				return processed.MarkProcessed(ctx, consumer, msg.MessageId, time.Now())
+			},
+		}
+		next(ctx, msg)
+	}
+}
+
+// processedAcknowledger records a delivery as processed when its handler acknowledges it
+type processedAcknowledger struct {
+	amqp.Acknowledger
+	record func() error
+}
+
+func (a *processedAcknowledger) Ack(tag uint64, multiple bool) error {
+	if err := a.record(); err != nil {
+		// The work is done either way, a redelivery would only be handled once more
+		log.Printf("Failed to record processed message: %v", err)
+	}
+	return a.Acknowledger.Ack(tag, multiple)
+}
+
+// unrecorded returns the delivery without its processed-message bookkeeping, for acknowledgements
+// that do not mean the message was handled, such as handing it over to a retry queue
+func unrecorded(msg amqp.Delivery) amqp.Delivery {
+	if ack, ok := msg.Acknowledger.(*processedAcknowledger); ok {
+		msg.Acknowledger = ack.Acknowledger
+	}
+	return msg
+}
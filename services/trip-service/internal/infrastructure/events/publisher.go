@@ -4,82 +4,135 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 
-	amqp "github.com/rabbitmq/amqp091-go"
 	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/services/trip-service/pkg/types"
 	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
 )
 
-// EventPublisher implements the EventPublisher interface
+// producerName identifies the trip service in the envelope of the events it publishes
+const producerName = "trip-service"
+
+// EventPublisher implements the EventPublisher interface by writing events to the outbox.
+// The OutboxRelay publishes them to RabbitMQ afterwards.
 type EventPublisher struct {
-	channel *amqp.Channel
+	outbox domain.OutboxRepository
 }
 
 // NewEventPublisher creates a new event publisher
-func NewEventPublisher(ch *amqp.Channel) (domain.EventPublisher, error) {
-	// Declare trip exchange
-	err := ch.ExchangeDeclare(
-		"trip_exchange", // name
-		"topic",         // type
-		true,            // durable
-		false,           // auto-deleted
-		false,           // internal
-		false,           // no-wait
-		nil,             // arguments
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to declare trip exchange: %w", err)
-	}
-
+func NewEventPublisher(outbox domain.OutboxRepository) domain.EventPublisher {
 	return &EventPublisher{
-		channel: ch,
-	}, nil
+		outbox: outbox,
+	}
 }
 
 // PublishTripCreated publishes a trip.event.created event
 func (p *EventPublisher) PublishTripCreated(ctx context.Context, trip *types.Trip) error {
-	return p.publishEvent(ctx, contracts.TripEventCreated, trip)
+	return messaging.Publish(ctx, p, contracts.TripEventCreatedTopic, trip.UserID, contracts.TripEventCreatedData{TripPayload: tripPayload(trip)})
+}
+
+// PublishTripScheduled publishes a trip.event.scheduled event
+func (p *EventPublisher) PublishTripScheduled(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventScheduledTopic, trip.UserID, contracts.TripEventScheduledData{TripPayload: tripPayload(trip)})
+}
+
+// PublishScheduledReminder publishes a trip.event.scheduled_reminder event
+func (p *EventPublisher) PublishScheduledReminder(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventScheduledReminderTopic, trip.UserID, contracts.TripEventScheduledReminderData{TripPayload: tripPayload(trip)})
+}
+
+// PublishPoolRiderJoined publishes a trip.event.pool_rider_joined event
+func (p *EventPublisher) PublishPoolRiderJoined(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventPoolRiderJoinedTopic, trip.UserID, contracts.TripEventPoolRiderJoinedData{TripPayload: tripPayload(trip)})
+}
+
+// PublishPoolRiderLeft publishes a trip.event.pool_rider_left event
+func (p *EventPublisher) PublishPoolRiderLeft(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventPoolRiderLeftTopic, trip.UserID, contracts.TripEventPoolRiderLeftData{TripPayload: tripPayload(trip)})
 }
 
 // PublishDriverAssigned publishes a trip.event.driver_assigned event
 func (p *EventPublisher) PublishDriverAssigned(ctx context.Context, trip *types.Trip) error {
-	return p.publishEvent(ctx, contracts.TripEventDriverAssigned, trip)
+	return messaging.Publish(ctx, p, contracts.TripEventDriverAssignedTopic, trip.UserID, contracts.TripEventDriverAssignedData{TripPayload: tripPayload(trip)})
 }
 
 // PublishNoDriversFound publishes a trip.event.no_drivers_found event
-func (p *EventPublisher) PublishNoDriversFound(ctx context.Context, tripID string) error {
-	data := map[string]string{
-		"trip_id": tripID,
-	}
-	return p.publishEvent(ctx, contracts.TripEventNoDriversFound, data)
+func (p *EventPublisher) PublishNoDriversFound(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventNoDriversFoundTopic, trip.UserID, contracts.TripEventNoDriversFoundData{
+		TripID: trip.ID,
+		UserID: trip.UserID,
+	})
+}
+
+// PublishTripCancelled publishes a trip.event.cancelled event
+func (p *EventPublisher) PublishTripCancelled(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventCancelledTopic, trip.UserID, contracts.TripEventCancelledData{TripPayload: tripPayload(trip)})
 }
 
-// publishEvent is a helper method to publish events to RabbitMQ
-func (p *EventPublisher) publishEvent(ctx context.Context, routingKey string, data interface{}) error {
-	body, err := json.Marshal(data)
+// PublishTripStarted publishes a trip.event.started event
+func (p *EventPublisher) PublishTripStarted(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventStartedTopic, trip.UserID, contracts.TripEventStartedData{TripPayload: tripPayload(trip)})
+}
+
+// PublishStopReached publishes a trip.event.stop_reached event
+func (p *EventPublisher) PublishStopReached(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventStopReachedTopic, trip.UserID, contracts.TripEventStopReachedData{TripPayload: tripPayload(trip)})
+}
+
+// PublishTripCompleted publishes a trip.event.completed event
+func (p *EventPublisher) PublishTripCompleted(ctx context.Context, trip *types.Trip) error {
+	return messaging.Publish(ctx, p, contracts.TripEventCompletedTopic, trip.UserID, contracts.TripEventCompletedData{TripPayload: tripPayload(trip)})
+}
+
+// PublishDriverTripRequest publishes a driver.cmd.trip_request offering the trip to a driver
+func (p *EventPublisher) PublishDriverTripRequest(ctx context.Context, trip *types.Trip, offer *types.DispatchAttempt) error {
+	return messaging.Publish(ctx, p, contracts.DriverCmdTripRequestTopic, offer.DriverID, contracts.DriverCmdTripRequestData{
+		DriverID:       offer.DriverID,
+		Trip:           trip,
+		OfferExpiresAt: &offer.ExpiresAt,
+	})
+}
+
+// PublishDriverNotInterested publishes a trip.event.driver_not_interested event
+func (p *EventPublisher) PublishDriverNotInterested(ctx context.Context, trip *types.Trip, driverID string) error {
+	return messaging.Publish(ctx, p, contracts.TripEventDriverNotInterestedTopic, trip.UserID, contracts.TripEventDriverNotInterestedData{
+		DriverID: driverID,
+		Trip:     trip,
+	})
+}
+
+// Send stores an event in the outbox, within the transaction of ctx if any. It implements messaging.Sender:
+// the event is wrapped in the versioned envelope, carrying the correlation ID and trace of ctx, and
+// rejected if data is not the payload type of the routing key.
+func (p *EventPublisher) Send(ctx context.Context, routingKey, ownerID string, data any) error {
+	envelope, err := messaging.NewMessage(ctx, producerName, routingKey, ownerID, data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event data: %w", err)
+		return err
 	}
 
-	msg := amqp.Publishing{
-		ContentType: "application/json",
-		Body:        body,
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event envelope: %w", err)
 	}
 
-	err = p.channel.PublishWithContext(
-		ctx,
-		"trip_exchange", // exchange
-		routingKey,      // routing key
-		false,           // mandatory
-		false,           // immediate
-		msg,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to publish event: %w", err)
+	msg := &types.OutboxMessage{
+		ID:         envelope.ID,
+		RoutingKey: routingKey,
+		Body:       body,
+		CreatedAt:  envelope.OccurredAt,
+	}
+	if aggregate, ok := data.(contracts.Aggregate); ok {
+		msg.AggregateID = aggregate.AggregateID()
 	}
 
-	log.Printf("Published event: %s", routingKey)
+	if err := p.outbox.Add(ctx, msg); err != nil {
+		return fmt.Errorf("failed to add event to outbox: %w", err)
+	}
 	return nil
 }
+
+// tripPayload is the payload shared by the trip events, the trip itself
+func tripPayload(trip *types.Trip) contracts.TripPayload {
+	return contracts.TripPayload{Trip: trip}
+}
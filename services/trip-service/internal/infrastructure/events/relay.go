@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/messaging"
+)
+
+const (
+	// relayBatchSize bounds how many outbox messages are published per poll
+	relayBatchSize = 100
+
+	// relayLease is how long a claimed message is left alone by other relays; a relay that dies
+	// mid-batch gives its messages back once it ends
+	relayLease = 30 * time.Second
+)
+
+// OutboxRelay publishes the messages of the outbox to trip_exchange and marks them sent once the
+// broker confirmed them. Messages are published at least once; a crash between the confirm and
+// marking them sent publishes them again.
+type OutboxRelay struct {
+	conn     *messaging.Connection
+	outbox   domain.OutboxRepository
+	interval time.Duration
+}
+
+// NewOutboxRelay creates a relay polling the outbox every interval
+func NewOutboxRelay(conn *messaging.Connection, outbox domain.OutboxRepository, interval time.Duration) (*OutboxRelay, error) {
+	// Declare trip exchange
+	err := conn.DeclareExchange(messaging.Exchange{Name: "trip_exchange", Kind: "topic"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxRelay{
+		conn:     conn,
+		outbox:   outbox,
+		interval: interval,
+	}, nil
+}
+
+// Start runs the relay in the background until the context is cancelled
+func (r *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.RelayPending(ctx); err != nil {
+					log.Printf("Failed to relay outbox messages: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Started outbox relay (every %v)", r.interval)
+}
+
+// RelayPending publishes a batch of pending messages, oldest first.
+// It stops at the first message the broker does not confirm, so the order of events is kept, and gives
+// back the leases of that message and the rest of the batch, which are retried on the next poll.
+//
+// A message no queue is bound for is not retried, since that would hold up every event behind it
+// until someone subscribes. It is marked failed and logged instead.
+func (r *OutboxRelay) RelayPending(ctx context.Context) error {
+	messages, err := r.outbox.ClaimPending(ctx, relayLease, relayBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	for i, msg := range messages {
+		if err := r.relay(ctx, msg); err != nil {
+			r.release(ctx, messages[i:])
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relay publishes a claimed message and records the outcome
+func (r *OutboxRelay) relay(ctx context.Context, msg *types.OutboxMessage) error {
+	err := r.publish(ctx, msg)
+	if errors.Is(err, messaging.ErrUnroutable) {
+		log.Printf("Dropping outbox message %s: %v", msg.ID, err)
+		if err := r.outbox.MarkFailed(ctx, msg.ID, time.Now(), err.Error()); err != nil {
+			return fmt.Errorf("failed to mark outbox message %s failed: %w", msg.ID, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to publish outbox message %s (attempt %d): %w", msg.ID, msg.Attempts, err)
+	}
+
+	if err := r.outbox.MarkSent(ctx, msg.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark outbox message %s sent: %w", msg.ID, err)
+	}
+
+	log.Printf("Published event: %s", msg.RoutingKey)
+	return nil
+}
+
+// release gives back the leases of messages that were claimed but not relayed. Until it succeeds they
+// stay locked until their lease ends, holding up the later events of their trips.
+func (r *OutboxRelay) release(ctx context.Context, messages []*types.OutboxMessage) {
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+
+	// Also when the relay stops because ctx was cancelled
+	if err := r.outbox.Release(context.WithoutCancel(ctx), ids); err != nil {
+		log.Printf("Failed to release %d outbox messages: %v", len(ids), err)
+	}
+}
+
+// publish sends a message and waits for the broker to confirm it
+func (r *OutboxRelay) publish(ctx context.Context, msg *types.OutboxMessage) error {
+	return r.conn.Publish(ctx, "trip_exchange", msg.RoutingKey, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.ID,
+		Timestamp:    msg.CreatedAt,
+		Body:         msg.Body,
+	})
+}
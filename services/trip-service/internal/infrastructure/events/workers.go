@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/retry"
+)
+
+// ConsumerConfig tunes how each consumer fetches, parallelises and retries its deliveries
+type ConsumerConfig struct {
+	Prefetch int          // deliveries a consumer holds unacknowledged at most
+	Workers  int          // deliveries a consumer handles in parallel
+	Retry    retry.Config // backoff of failed driver responses before they are dead-lettered
+}
+
+// DefaultConsumerConfig returns a ConsumerConfig with sensible default values
+func DefaultConsumerConfig() ConsumerConfig {
+	return ConsumerConfig{
+		Prefetch: 32,
+		Workers:  8,
+		Retry: retry.Config{
+			MaxRetries:  5,
+			InitialWait: 1 * time.Second,
+			MaxWait:     60 * time.Second,
+		},
+	}
+}
+
+// partitionKey picks the deliveries that must be handled one after the other, in the order they arrived
+type partitionKey func(msg amqp.Delivery) string
+
+// tripKey partitions driver commands by trip
+func tripKey(msg amqp.Delivery) string {
+	var command struct {
+		TripID string `json:"tripID"`
+	}
+	_ = json.Unmarshal(payloadOf(msg), &command)
+	return command.TripID
+}
+
+// driverKey partitions driver locations by driver
+func driverKey(msg amqp.Delivery) string {
+	var driver struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(payloadOf(msg), &driver)
+	return driver.ID
+}
+
+// payloadOf returns the payload inside the envelope of a delivery, or nil if it cannot be read
+func payloadOf(msg amqp.Delivery) []byte {
+	envelope, err := messaging.DecodeMessage(msg.Body)
+	if err != nil {
+		return nil
+	}
+	return envelope.Data
+}
+
+// runWorkers hands deliveries to a fixed number of workers until msgs is closed, then waits for the
+// workers to finish what they were given. Deliveries with the same key always go to the same worker,
+// so they are handled in order while other keys run in parallel.
+func runWorkers(ctx context.Context, msgs <-chan amqp.Delivery, workers, buffer int, key partitionKey, handler Handler) {
+	var wg sync.WaitGroup
+	queues := make([]chan amqp.Delivery, max(workers, 1))
+	for i := range queues {
+		// Room for every prefetched delivery, so a busy worker never holds up the others
+		queues[i] = make(chan amqp.Delivery, buffer)
+
+		wg.Add(1)
+		go func(queue <-chan amqp.Delivery) {
+			defer wg.Done()
+			for msg := range queue {
+				handler(ctx, msg)
+			}
+		}(queues[i])
+	}
+
+	for msg := range msgs {
+		hash := fnv.New32a()
+		hash.Write([]byte(key(msg)))
+		queues[hash.Sum32()%uint32(len(queues))] <- msg
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+}
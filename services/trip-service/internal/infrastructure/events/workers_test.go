@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// testAcknowledger counts the deliveries handlers acknowledged
+type testAcknowledger struct {
+	acked atomic.Int64
+}
+
+func (a *testAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked.Add(1)
+	return nil
+}
+
+func (a *testAcknowledger) Nack(tag uint64, multiple, requeue bool) error { return nil }
+
+func (a *testAcknowledger) Reject(tag uint64, requeue bool) error { return nil }
+
+// typeKey partitions test deliveries by their Type
+func typeKey(msg amqp.Delivery) string {
+	return msg.Type
+}
+
+func startWorkers(workers, buffer int, handler Handler) (chan<- amqp.Delivery, <-chan struct{}) {
+	msgs := make(chan amqp.Delivery)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runWorkers(context.Background(), msgs, workers, buffer, typeKey, handler)
+	}()
+	return msgs, done
+}
+
+func waitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runWorkers did not return after msgs was closed")
+	}
+}
+
+func TestRunWorkersKeepsKeyOrder(t *testing.T) {
+	const keys, perKey = 6, 50
+
+	var mu sync.Mutex
+	seen := make(map[string][]uint64)
+	var inFlight [keys]atomic.Int32
+	var overlapped atomic.Bool
+
+	msgs, done := startWorkers(4, 16, func(ctx context.Context, msg amqp.Delivery) {
+		var k int
+		fmt.Sscanf(msg.Type, "key-%d", &k)
+		if inFlight[k].Add(1) > 1 {
+			overlapped.Store(true)
+		}
+		defer inFlight[k].Add(-1)
+
+		time.Sleep(100 * time.Microsecond)
+
+		mu.Lock()
+		seen[msg.Type] = append(seen[msg.Type], msg.DeliveryTag)
+		mu.Unlock()
+	})
+
+	// Interleave the keys, numbering each key's deliveries in the order they are sent
+	for i := 1; i <= perKey; i++ {
+		for k := 0; k < keys; k++ {
+			msgs <- amqp.Delivery{Type: fmt.Sprintf("key-%d", k), DeliveryTag: uint64(i)}
+		}
+	}
+	close(msgs)
+	waitDone(t, done)
+
+	if overlapped.Load() {
+		t.Error("deliveries of the same key were handled concurrently")
+	}
+	for k := 0; k < keys; k++ {
+		key := fmt.Sprintf("key-%d", k)
+		tags := seen[key]
+		if len(tags) != perKey {
+			t.Fatalf("%s: handled %d deliveries, want %d", key, len(tags), perKey)
+		}
+		for i, tag := range tags {
+			if tag != uint64(i+1) {
+				t.Fatalf("%s: delivery %d handled at position %d, want in order %v", key, tag, i+1, tags)
+			}
+		}
+	}
+}
+
+func TestRunWorkersDoesNotBlockOtherKeys(t *testing.T) {
+	const workers = 4
+	slow, fast := keysOnDifferentWorkers(workers)
+
+	release := make(chan struct{})
+	fastHandled := make(chan struct{})
+	msgs, done := startWorkers(workers, 4, func(ctx context.Context, msg amqp.Delivery) {
+		switch msg.Type {
+		case slow:
+			<-release
+		case fast:
+			close(fastHandled)
+		}
+	})
+
+	msgs <- amqp.Delivery{Type: slow}
+	msgs <- amqp.Delivery{Type: fast}
+
+	select {
+	case <-fastHandled:
+	case <-time.After(5 * time.Second):
+		t.Error("a busy worker held up a delivery of another key")
+	}
+
+	close(release)
+	close(msgs)
+	waitDone(t, done)
+}
+
+func TestRunWorkersDrainsQueuesOnShutdown(t *testing.T) {
+	const deliveries = 40
+
+	acks := &testAcknowledger{}
+	msgs, done := startWorkers(2, deliveries, func(ctx context.Context, msg amqp.Delivery) {
+		time.Sleep(time.Millisecond)
+		_ = msg.Ack(false)
+	})
+
+	// The queues have room for every delivery, so most are still queued when msgs is closed
+	for i := 0; i < deliveries; i++ {
+		msgs <- amqp.Delivery{Acknowledger: acks, Type: fmt.Sprintf("key-%d", i%4), DeliveryTag: uint64(i + 1)}
+	}
+	close(msgs)
+	waitDone(t, done)
+
+	if got := acks.acked.Load(); got != deliveries {
+		t.Errorf("runWorkers returned after %d of %d deliveries were handled", got, deliveries)
+	}
+}
+
+// keysOnDifferentWorkers returns two keys runWorkers hands to different workers
+func keysOnDifferentWorkers(workers int) (string, string) {
+	worker := func(key string) uint32 {
+		hash := fnv.New32a()
+		hash.Write([]byte(key))
+		return hash.Sum32() % uint32(workers)
+	}
+
+	first := "key-0"
+	for i := 1; ; i++ {
+		if key := fmt.Sprintf("key-%d", i); worker(key) != worker(first) {
+			return first, key
+		}
+	}
+}
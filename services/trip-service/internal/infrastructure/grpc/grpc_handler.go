@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	pb "ride-sharing/proto/trip"
+	"ride-sharing/services/trip-service/internal/domain"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxWaypoints is how many intermediate stops a trip may have
+const maxWaypoints = 5
+
+// gRPCHandler exposes the TripService over gRPC
+type gRPCHandler struct {
+	pb.UnimplementedTripServiceServer
+
+	service domain.TripService
+}
+
+// NewGRPCHandler registers the trip service handlers on the given server
+func NewGRPCHandler(server *grpc.Server, service domain.TripService) *gRPCHandler {
+	handler := &gRPCHandler{
+		service: service,
+	}
+
+	pb.RegisterTripServiceServer(server, handler)
+	return handler
+}
+
+// PreviewTrip calculates route and fare options without creating a trip
+func (h *gRPCHandler) PreviewTrip(ctx context.Context, req *pb.PreviewTripRequest) (*pb.PreviewTripResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetPickup() == nil || req.GetDestination() == nil {
+		return nil, status.Error(codes.InvalidArgument, "pickup and destination are required")
+	}
+	if len(req.GetWaypoints()) > maxWaypoints {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d waypoints are allowed", maxWaypoints)
+	}
+
+	route, fares, err := h.service.PreviewTrip(ctx, req.GetUserId(), coordinateFromProto(req.GetPickup()), coordinateFromProto(req.GetDestination()), coordinatesFromProto(req.GetWaypoints()), timeFromUnix(req.GetPickupAt()), req.GetPromoCode())
+	if err != nil {
+		log.Printf("Failed to preview trip: %v", err)
+		return nil, toStatusError(err, "failed to preview trip")
+	}
+
+	return &pb.PreviewTripResponse{
+		Route:     routeToProto(route),
+		RideFares: routeFaresToProto(fares),
+	}, nil
+}
+
+// CreateTrip creates a new trip with the selected fare
+func (h *gRPCHandler) CreateTrip(ctx context.Context, req *pb.CreateTripRequest) (*pb.CreateTripResponse, error) {
+	if req.GetUserId() == "" || req.GetFareId() == "" || req.GetFareToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id, fare_id and fare_token are required")
+	}
+
+	trip, err := h.service.CreateTrip(ctx, req.GetUserId(), req.GetFareId(), req.GetFareToken(), req.GetPromoCode())
+	if err != nil {
+		log.Printf("Failed to create trip: %v", err)
+		return nil, toStatusError(err, "failed to create trip")
+	}
+
+	return &pb.CreateTripResponse{
+		TripId: trip.ID,
+		Status: tripStatusToProto(trip.Status),
+	}, nil
+}
+
+// CancelTrip cancels a trip on behalf of its rider or assigned driver
+func (h *gRPCHandler) CancelTrip(ctx context.Context, req *pb.CancelTripRequest) (*pb.CancelTripResponse, error) {
+	if req.GetTripId() == "" || req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "trip_id and user_id are required")
+	}
+
+	actor, ok := tripActorFromProto(req.GetCancelledBy())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "cancelled_by must be rider or driver")
+	}
+
+	trip, cancellation, err := h.service.CancelTrip(ctx, req.GetTripId(), req.GetUserId(), actor, req.GetReason())
+	if err != nil {
+		log.Printf("Failed to cancel trip: %v", err)
+		return nil, toStatusError(err, "failed to cancel trip")
+	}
+
+	return &pb.CancelTripResponse{
+		TripId:                 trip.ID,
+		Status:                 tripStatusToProto(trip.Status),
+		CancellationFeeInCents: cancellation.FeeInCents,
+	}, nil
+}
+
+// toStatusError maps domain errors to gRPC status codes. Callers log err before calling it.
+func toStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrFareNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrFareNotOwned):
+		return status.Errorf(codes.PermissionDenied, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrFareExpired):
+		return statusWithReason(codes.FailedPrecondition, "FARE_EXPIRED", fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, domain.ErrFareTokenInvalid):
+		return statusWithReason(codes.InvalidArgument, "INVALID_FARE_TOKEN", fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, domain.ErrFareAlreadyBooked):
+		return statusWithReason(codes.AlreadyExists, "FARE_ALREADY_BOOKED", fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, domain.ErrTripNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrStatusConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrNotTripParticipant):
+		return status.Errorf(codes.PermissionDenied, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrInvalidPickupTime):
+		return statusWithReason(codes.InvalidArgument, "INVALID_PICKUP_TIME", fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, domain.ErrPromoNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrPromoNotApplicable), errors.Is(err, domain.ErrPromoLimitReached):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	default:
+		// Unexpected errors may name collections, hosts or queries; callers log them, clients only get msg
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+// statusWithReason attaches a machine readable reason to a status, for clients that need
+// to tell apart errors sharing a gRPC code
+func statusWithReason(code codes.Code, reason, msg string) error {
+	st, err := status.New(code, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: "trip-service",
+	})
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
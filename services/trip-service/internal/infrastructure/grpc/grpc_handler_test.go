@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "ride-sharing/proto/trip"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/service"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// The fakes embed the interfaces they stand in for, so a call the test did not expect panics
+
+type fakeTripRepository struct {
+	domain.TripRepository
+	trips map[string]*types.Trip
+}
+
+func (r *fakeTripRepository) GetByID(ctx context.Context, id string) (*types.Trip, error) {
+	return r.trips[id], nil
+}
+
+func (r *fakeTripRepository) Update(ctx context.Context, trip *types.Trip, expected types.TripStatus) error {
+	r.trips[trip.ID] = trip
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeEventPublisher struct {
+	domain.EventPublisher
+	riderLeft []*types.Trip
+}
+
+func (p *fakeEventPublisher) PublishPoolRiderLeft(ctx context.Context, trip *types.Trip) error {
+	p.riderLeft = append(p.riderLeft, trip)
+	return nil
+}
+
+type fakeOSRMClient struct{}
+
+func (fakeOSRMClient) GetRoute(ctx context.Context, pickup, destination *types.Coordinate, waypoints []*types.Coordinate) (*types.Route, error) {
+	return &types.Route{Distance: 5000, Duration: 600, Legs: make([]*types.RouteLeg, len(waypoints)+1)}, nil
+}
+
+func TestCancelTripRiderLeavesPool(t *testing.T) {
+	assignedAt := time.Now().Add(-10 * time.Minute)
+	trip := &types.Trip{
+		ID:               "trip-1",
+		UserID:           "rider-1",
+		Status:           types.TripStatusDriverAssigned,
+		Driver:           &types.Driver{ID: "driver-1"},
+		DriverAssignedAt: &assignedAt,
+		Stops: []*types.TripStop{
+			{Location: &types.Coordinate{Latitude: 52.50, Longitude: 13.40}, Kind: types.TripStopPickup, UserID: "rider-1"},
+			{Location: &types.Coordinate{Latitude: 52.51, Longitude: 13.41}, Kind: types.TripStopPickup, UserID: "rider-2"},
+			{Location: &types.Coordinate{Latitude: 52.52, Longitude: 13.42}, Kind: types.TripStopDropoff, UserID: "rider-2"},
+			{Location: &types.Coordinate{Latitude: 52.53, Longitude: 13.43}, Kind: types.TripStopDropoff, UserID: "rider-1"},
+		},
+		Riders: []*types.PoolRider{
+			{UserID: "rider-1", FareID: "fare-1", FareInCents: 900},
+			{UserID: "rider-2", FareID: "fare-2", FareInCents: 700},
+		},
+	}
+
+	cfg := service.DefaultConfig()
+	repo := &fakeTripRepository{trips: map[string]*types.Trip{trip.ID: trip}}
+	events := &fakeEventPublisher{}
+	svc := service.NewTripService(cfg, fakeTransactor{}, repo, nil, nil, nil, fakeOSRMClient{}, nil, nil, events, nil)
+	handler := &gRPCHandler{service: svc}
+
+	resp, err := handler.CancelTrip(context.Background(), &pb.CancelTripRequest{
+		TripId:      trip.ID,
+		UserId:      "rider-2",
+		CancelledBy: pb.TripActor_TRIP_ACTOR_RIDER,
+		Reason:      "plans changed",
+	})
+	if err != nil {
+		t.Fatalf("CancelTrip: %v", err)
+	}
+
+	// The trip goes on for the other rider, the one who left pays the late cancellation fee
+	if resp.GetStatus() != pb.TripStatus_TRIP_STATUS_DRIVER_ASSIGNED {
+		t.Errorf("status = %v, want the trip to stay driver assigned", resp.GetStatus())
+	}
+	if resp.GetCancellationFeeInCents() != cfg.CancellationFeeInCents {
+		t.Errorf("cancellation fee = %d, want %d", resp.GetCancellationFeeInCents(), cfg.CancellationFeeInCents)
+	}
+
+	stored := repo.trips[trip.ID]
+	if stored.Cancellation != nil {
+		t.Errorf("trip cancelled by %s, want only the rider to leave", stored.Cancellation.ActorID)
+	}
+	if len(stored.Stops) != 2 {
+		t.Errorf("trip has %d stops, want the leaving rider's 2 stops dropped", len(stored.Stops))
+	}
+	if len(events.riderLeft) != 1 {
+		t.Errorf("published %d pool rider left events, want 1", len(events.riderLeft))
+	}
+}
@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"time"
+
+	pb "ride-sharing/proto/trip"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+func coordinateFromProto(c *pb.Coordinate) *types.Coordinate {
+	return &types.Coordinate{
+		Latitude:  c.GetLatitude(),
+		Longitude: c.GetLongitude(),
+	}
+}
+
+func coordinatesFromProto(coordinates []*pb.Coordinate) []*types.Coordinate {
+	result := make([]*types.Coordinate, 0, len(coordinates))
+	for _, c := range coordinates {
+		result = append(result, coordinateFromProto(c))
+	}
+	return result
+}
+
+func coordinateToProto(c *types.Coordinate) *pb.Coordinate {
+	return &pb.Coordinate{
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+	}
+}
+
+func routeToProto(route *types.Route) *pb.Route {
+	if route == nil {
+		return nil
+	}
+
+	geometry := make([]*pb.Geometry, 0, len(route.Geometry))
+	for _, g := range route.Geometry {
+		coordinates := make([]*pb.Coordinate, 0, len(g.Coordinates))
+		for _, c := range g.Coordinates {
+			coordinates = append(coordinates, coordinateToProto(c))
+		}
+		geometry = append(geometry, &pb.Geometry{Coordinates: coordinates})
+	}
+
+	legs := make([]*pb.RouteLeg, 0, len(route.Legs))
+	for _, leg := range route.Legs {
+		legs = append(legs, &pb.RouteLeg{Distance: leg.Distance, Duration: leg.Duration})
+	}
+
+	return &pb.Route{
+		Distance: route.Distance,
+		Duration: route.Duration,
+		Geometry: geometry,
+		Legs:     legs,
+	}
+}
+
+func routeFaresToProto(fares []*types.RouteFare) []*pb.RouteFare {
+	result := make([]*pb.RouteFare, 0, len(fares))
+	for _, fare := range fares {
+		result = append(result, &pb.RouteFare{
+			Id:                fare.ID,
+			PackageSlug:       string(fare.PackageSlug),
+			BasePrice:         float64(fare.TotalPriceInCents) / 100, // deprecated proto field, in dollars
+			TotalPriceInCents: fare.TotalPriceInCents,
+			ExpiresAt:         fare.ExpiresAt.Unix(),
+			Route:             routeToProto(fare.Route),
+			Breakdown:         fareLineItemsToProto(fare.Breakdown),
+			PricingRegion:     fare.PricingRegion,
+			PricingVersion:    fare.PricingVersion,
+			SurgeMultiplier:   fare.SurgeMultiplier,
+			PromoCode:         fare.PromoCode,
+			DiscountInCents:   fare.DiscountInCents,
+			Token:             fare.Token,
+			PickupAt:          unixOrZero(fare.PickupAt),
+		})
+	}
+	return result
+}
+
+func fareLineItemsToProto(items []*types.FareLineItem) []*pb.FareLineItem {
+	result := make([]*pb.FareLineItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, &pb.FareLineItem{
+			Type:          string(item.Type),
+			AmountInCents: item.AmountInCents,
+		})
+	}
+	return result
+}
+
+func tripStatusToProto(status types.TripStatus) pb.TripStatus {
+	switch status {
+	case types.TripStatusPending:
+		return pb.TripStatus_TRIP_STATUS_PENDING
+	case types.TripStatusCreated:
+		return pb.TripStatus_TRIP_STATUS_CREATED
+	case types.TripStatusDriverFound:
+		return pb.TripStatus_TRIP_STATUS_DRIVER_FOUND
+	case types.TripStatusDriverAssigned:
+		return pb.TripStatus_TRIP_STATUS_DRIVER_ASSIGNED
+	case types.TripStatusInProgress:
+		return pb.TripStatus_TRIP_STATUS_IN_PROGRESS
+	case types.TripStatusCompleted:
+		return pb.TripStatus_TRIP_STATUS_COMPLETED
+	case types.TripStatusCancelled:
+		return pb.TripStatus_TRIP_STATUS_CANCELLED
+	case types.TripStatusScheduled:
+		return pb.TripStatus_TRIP_STATUS_SCHEDULED
+	default:
+		return pb.TripStatus_TRIP_STATUS_UNSPECIFIED
+	}
+}
+
+func tripActorFromProto(actor pb.TripActor) (types.TripActor, bool) {
+	switch actor {
+	case pb.TripActor_TRIP_ACTOR_RIDER:
+		return types.TripActorRider, true
+	case pb.TripActor_TRIP_ACTOR_DRIVER:
+		return types.TripActorDriver, true
+	default:
+		return "", false
+	}
+}
+
+// unixOrZero converts an optional time to a Unix timestamp, 0 when unset
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+// timeFromUnix converts an optional Unix timestamp, where 0 means unset
+func timeFromUnix(sec int64) *time.Time {
+	if sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0)
+	return &t
+}
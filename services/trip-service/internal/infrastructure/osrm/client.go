@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+
 	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/services/trip-service/pkg/types"
 	"ride-sharing/shared/env"
@@ -21,7 +23,7 @@ type OSRMClient struct {
 // NewOSRMClient creates a new OSRM client
 func NewOSRMClient() domain.OSRMClient {
 	baseURL := env.GetString("OSRM_URL", "http://router.project-osrm.org")
-	
+
 	return &OSRMClient{
 		baseURL: baseURL,
 		client:  &http.Client{},
@@ -36,9 +38,16 @@ type OSRMResponse struct {
 
 // OSRMRoute represents a route in OSRM response
 type OSRMRoute struct {
-	Distance float64            `json:"distance"` // in meters
-	Duration float64            `json:"duration"` // in seconds
-	Geometry OSRMGeometry       `json:"geometry"` // GeoJSON geometry
+	Distance float64      `json:"distance"` // in meters
+	Duration float64      `json:"duration"` // in seconds
+	Geometry OSRMGeometry `json:"geometry"` // GeoJSON geometry
+	Legs     []OSRMLeg    `json:"legs"`     // one per pair of consecutive coordinates
+}
+
+// OSRMLeg represents the route between two consecutive coordinates of the request
+type OSRMLeg struct {
+	Distance float64 `json:"distance"` // in meters
+	Duration float64 `json:"duration"` // in seconds
 }
 
 // OSRMGeometry represents the GeoJSON geometry from OSRM
@@ -47,48 +56,51 @@ type OSRMGeometry struct {
 	Coordinates [][]float64 `json:"coordinates"` // [lon, lat] pairs
 }
 
-// GetRoute calculates a route between two coordinates
-func (c *OSRMClient) GetRoute(ctx context.Context, pickup, destination *types.Coordinate) (*types.Route, error) {
-	// OSRM API format: /route/v1/driving/{lon1},{lat1};{lon2},{lat2}
-	endpoint := fmt.Sprintf("%s/route/v1/driving/%f,%f;%f,%f",
-		c.baseURL,
-		pickup.Longitude, pickup.Latitude,
-		destination.Longitude, destination.Latitude,
-	)
-	
+// GetRoute calculates a route from pickup to destination through the waypoints, in order
+func (c *OSRMClient) GetRoute(ctx context.Context, pickup, destination *types.Coordinate, waypoints []*types.Coordinate) (*types.Route, error) {
+	// OSRM API format: /route/v1/driving/{lon1},{lat1};{lon2},{lat2};...
+	stops := make([]string, 0, len(waypoints)+2)
+	stops = append(stops, fmt.Sprintf("%f,%f", pickup.Longitude, pickup.Latitude))
+	for _, waypoint := range waypoints {
+		stops = append(stops, fmt.Sprintf("%f,%f", waypoint.Longitude, waypoint.Latitude))
+	}
+	stops = append(stops, fmt.Sprintf("%f,%f", destination.Longitude, destination.Latitude))
+
+	endpoint := fmt.Sprintf("%s/route/v1/driving/%s", c.baseURL, strings.Join(stops, ";"))
+
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	// Request geometry as encoded polyline
 	q := url.Values{}
 	q.Set("overview", "full")
 	q.Set("geometries", "geojson")
 	req.URL.RawQuery = q.Encode()
-	
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("OSRM API error: status %d, body: %s", resp.StatusCode, string(body))
 	}
-	
+
 	var osrmResp OSRMResponse
 	if err := json.NewDecoder(resp.Body).Decode(&osrmResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	
+
 	if osrmResp.Code != "Ok" || len(osrmResp.Routes) == 0 {
 		return nil, fmt.Errorf("no route found: code %s", osrmResp.Code)
 	}
-	
+
 	route := osrmResp.Routes[0]
-	
+
 	// Convert coordinates from OSRM format [lon, lat] to our format
 	coordinates := make([]*types.Coordinate, 0, len(route.Geometry.Coordinates))
 	for _, coord := range route.Geometry.Coordinates {
@@ -99,7 +111,15 @@ func (c *OSRMClient) GetRoute(ctx context.Context, pickup, destination *types.Co
 			})
 		}
 	}
-	
+
+	legs := make([]*types.RouteLeg, 0, len(route.Legs))
+	for _, leg := range route.Legs {
+		legs = append(legs, &types.RouteLeg{
+			Distance: leg.Distance,
+			Duration: leg.Duration,
+		})
+	}
+
 	return &types.Route{
 		Distance: route.Distance,
 		Duration: route.Duration,
@@ -108,5 +128,6 @@ func (c *OSRMClient) GetRoute(ctx context.Context, pickup, destination *types.Co
 				Coordinates: coordinates,
 			},
 		},
+		Legs: legs,
 	}, nil
 }
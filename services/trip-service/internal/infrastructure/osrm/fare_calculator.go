@@ -2,58 +2,268 @@ package osrm
 
 import (
 	"context"
-	"ride-sharing/services/trip-service/internal/domain"
-	"ride-sharing/services/trip-service/pkg/types"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mmcloughlin/geohash"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
 )
 
-// FareCalculator implements fare calculation logic
+// fareValidity is how long a quoted fare can be booked
+const fareValidity = 5 * time.Minute
+
+// defaultRegion is the region used for pickups outside every configured region
+const defaultRegion = "default"
+
+// builtinPricing is used until a pricing table for the default region is configured.
+// Its version is 0 so fares priced with it are easy to tell apart.
+var builtinPricing = &types.PricingTable{
+	Region:  defaultRegion,
+	Version: 0,
+	Packages: map[types.CarPackageSlug]types.PackagePricing{
+		types.CarPackageSedan:  {BaseFeeCents: 200, PerKmCents: 150, PerMinuteCents: 25, MinimumFareCents: 500, BookingFeeCents: 150, StopFeeCents: 100},
+		types.CarPackageSUV:    {BaseFeeCents: 250, PerKmCents: 180, PerMinuteCents: 30, MinimumFareCents: 700, BookingFeeCents: 150, StopFeeCents: 100},
+		types.CarPackageVAN:    {BaseFeeCents: 300, PerKmCents: 200, PerMinuteCents: 35, MinimumFareCents: 800, BookingFeeCents: 150, StopFeeCents: 100},
+		types.CarPackageLuxury: {BaseFeeCents: 500, PerKmCents: 300, PerMinuteCents: 50, MinimumFareCents: 1200, BookingFeeCents: 200, StopFeeCents: 150},
+		types.CarPackagePool:   {BaseFeeCents: 150, PerKmCents: 110, PerMinuteCents: 18, MinimumFareCents: 400, BookingFeeCents: 100},
+	},
+}
+
+// FareCalculator implements fare calculation logic.
+// All amounts are integer cents; every line item is rounded half-up to the nearest cent.
+// Pricing tables are kept in memory and refreshed from the repository by Start.
 type FareCalculator struct {
-	basePrices map[types.CarPackageSlug]float64
+	repo   domain.PricingRepository
+	surge  domain.SurgePricer
+	tables atomic.Pointer[[]*types.PricingTable]
 }
 
 // NewFareCalculator creates a new fare calculator
-func NewFareCalculator() domain.FareCalculator {
-	return &FareCalculator{
-		basePrices: map[types.CarPackageSlug]float64{
-			types.CarPackageSedan:   2.50,  // $2.50 per km
-			types.CarPackageSUV:     3.00,  // $3.00 per km
-			types.CarPackageVAN:    3.50,  // $3.50 per km
-			types.CarPackageLuxury: 5.00,  // $5.00 per km
-		},
-	}
-}
-
-// CalculateFares calculates fare options for a given route
-func (fc *FareCalculator) CalculateFares(ctx context.Context, route *types.Route) ([]*types.RouteFare, error) {
-	// Convert distance from meters to kilometers
-	distanceKm := route.Distance / 1000.0
-	
-	fares := make([]*types.RouteFare, 0, len(fc.basePrices))
-	expiresAt := time.Now().Add(5 * time.Minute) // Fares expire in 5 minutes
-	
-	for packageSlug, basePricePerKm := range fc.basePrices {
-		basePrice := basePricePerKm * distanceKm
-		
-		// Add base fee
-		basePrice += 2.0 // $2.00 base fee
-		
-		// Convert to cents
-		totalPriceInCents := int64(basePrice * 100)
-		
-		fare := &types.RouteFare{
+func NewFareCalculator(repo domain.PricingRepository, surge domain.SurgePricer) *FareCalculator {
+	fc := &FareCalculator{
+		repo:  repo,
+		surge: surge,
+	}
+	fc.tables.Store(&[]*types.PricingTable{})
+	return fc
+}
+
+// Start loads the pricing tables and keeps reloading them in the background until ctx is cancelled
+func (fc *FareCalculator) Start(ctx context.Context, interval time.Duration) {
+	if err := fc.Reload(ctx); err != nil {
+		log.Printf("Failed to load pricing tables, using built-in pricing: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fc.Reload(ctx); err != nil {
+					log.Printf("Failed to reload pricing tables: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Reload replaces the in-memory pricing tables with the latest version of every region
+func (fc *FareCalculator) Reload(ctx context.Context) error {
+	tables, err := fc.repo.ListLatest(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]int64)
+	for _, table := range *fc.tables.Load() {
+		previous[table.Region] = table.Version
+	}
+	for _, table := range tables {
+		if version, ok := previous[table.Region]; !ok || version != table.Version {
+			log.Printf("Loaded pricing for region %s, version %d", table.Region, table.Version)
+		}
+	}
+
+	fc.tables.Store(&tables)
+	return nil
+}
+
+// tableFor returns the pricing table of the region the pickup is in.
+// The most specific geohash prefix wins; pickups outside every region use the default region.
+func (fc *FareCalculator) tableFor(pickup *types.Coordinate) *types.PricingTable {
+	tables := *fc.tables.Load()
+
+	var fallback, best *types.PricingTable
+	bestLen := 0
+
+	hash := ""
+	if pickup != nil {
+		hash = geohash.Encode(pickup.Latitude, pickup.Longitude)
+	}
+
+	for _, table := range tables {
+		if table.Region == defaultRegion {
+			fallback = table
+		}
+		if hash == "" {
+			continue
+		}
+		for _, prefix := range table.GeohashPrefixes {
+			if len(prefix) > bestLen && strings.HasPrefix(hash, prefix) {
+				best, bestLen = table, len(prefix)
+			}
+		}
+	}
+
+	switch {
+	case best != nil:
+		return best
+	case fallback != nil:
+		return fallback
+	default:
+		return builtinPricing
+	}
+}
+
+// CalculateFares calculates fare options for a given route, priced for the region of the pickup
+func (fc *FareCalculator) CalculateFares(ctx context.Context, route *types.Route, pickup *types.Coordinate) ([]*types.RouteFare, error) {
+	table := fc.tableFor(pickup)
+	fares := make([]*types.RouteFare, 0, len(table.Packages))
+	expiresAt := time.Now().Add(fareValidity)
+
+	// A failing surge lookup must not block quotes, so it falls back to no surge
+	surgeMultiplier, err := fc.surge.Multiplier(ctx, pickup)
+	if err != nil {
+		log.Printf("Failed to get surge multiplier, pricing without surge: %v", err)
+		surgeMultiplier = 1
+	}
+
+	for packageSlug, pricing := range table.Packages {
+		breakdown := PriceRoute(pricing, route, routeStops(route), surgeMultiplier)
+		total := types.SumLineItems(breakdown)
+
+		fares = append(fares, &types.RouteFare{
 			ID:                uuid.New().String(),
 			PackageSlug:       packageSlug,
-			BasePrice:         basePrice,
-			TotalPriceInCents: totalPriceInCents,
+			TotalPriceInCents: total,
+			Breakdown:         breakdown,
+			PricingRegion:     table.Region,
+			PricingVersion:    table.Version,
+			SurgeMultiplier:   surgeMultiplier,
 			ExpiresAt:         expiresAt,
 			Route:             route,
-		}
-		
-		fares = append(fares, fare)
+		})
 	}
-	
+
+	// Cheapest first, so the order is stable across previews
+	sort.Slice(fares, func(i, j int) bool {
+		return fares[i].TotalPriceInCents < fares[j].TotalPriceInCents
+	})
+
 	return fares, nil
 }
+
+// RepriceFare prices a route with the pricing version and surge a fare was quoted with
+func (fc *FareCalculator) RepriceFare(ctx context.Context, fare *types.RouteFare, route *types.Route) ([]*types.FareLineItem, error) {
+	table, err := fc.tableVersion(ctx, fare.PricingRegion, fare.PricingVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, ok := table.Packages[fare.PackageSlug]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s v%d has no %s pricing", domain.ErrPricingVersionNotFound, fare.PricingRegion, fare.PricingVersion, fare.PackageSlug)
+	}
+
+	return PriceRoute(pricing, route, len(fare.Waypoints), max(fare.SurgeMultiplier, 1)), nil
+}
+
+// tableVersion returns a specific pricing table, preferring the ones already in memory
+func (fc *FareCalculator) tableVersion(ctx context.Context, region string, version int64) (*types.PricingTable, error) {
+	if region == builtinPricing.Region && version == builtinPricing.Version {
+		return builtinPricing, nil
+	}
+
+	for _, table := range *fc.tables.Load() {
+		if table.Region == region && table.Version == version {
+			return table, nil
+		}
+	}
+
+	table, err := fc.repo.GetVersion(ctx, region, version)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("%w: %s v%d", domain.ErrPricingVersionNotFound, region, version)
+	}
+	return table, nil
+}
+
+// PriceRoute itemises the price of a route with the given number of intermediate stops for a package:
+// max(base + distance + time + stop fees, minimum fare) * surge + booking fee
+func PriceRoute(pricing types.PackagePricing, route *types.Route, stops int, surgeMultiplier float64) []*types.FareLineItem {
+	meters := int64(math.Round(route.Distance))
+	seconds := int64(math.Round(route.Duration))
+
+	distance := roundDiv(pricing.PerKmCents*meters, 1000)
+	duration := roundDiv(pricing.PerMinuteCents*seconds, 60)
+
+	breakdown := []*types.FareLineItem{
+		{Type: types.FareLineItemBaseFee, AmountInCents: pricing.BaseFeeCents},
+		{Type: types.FareLineItemDistance, AmountInCents: distance},
+		{Type: types.FareLineItemTime, AmountInCents: duration},
+	}
+
+	if stops > 0 && pricing.StopFeeCents > 0 {
+		breakdown = append(breakdown, &types.FareLineItem{
+			Type:          types.FareLineItemStopFee,
+			AmountInCents: pricing.StopFeeCents * int64(stops),
+		})
+	}
+
+	if subtotal := types.SumLineItems(breakdown); subtotal < pricing.MinimumFareCents {
+		breakdown = append(breakdown, &types.FareLineItem{
+			Type:          types.FareLineItemMinimumFareAdjustment,
+			AmountInCents: pricing.MinimumFareCents - subtotal,
+		})
+	}
+
+	// The multiplier is applied in whole percent so the surge amount is exact in cents
+	if surgePercent := int64(math.Round(surgeMultiplier * 100)); surgePercent > 100 {
+		breakdown = append(breakdown, &types.FareLineItem{
+			Type:          types.FareLineItemSurge,
+			AmountInCents: roundDiv(types.SumLineItems(breakdown)*(surgePercent-100), 100),
+		})
+	}
+
+	if pricing.BookingFeeCents > 0 {
+		breakdown = append(breakdown, &types.FareLineItem{
+			Type:          types.FareLineItemBookingFee,
+			AmountInCents: pricing.BookingFeeCents,
+		})
+	}
+
+	return breakdown
+}
+
+// routeStops returns the number of intermediate stops of a route, one less than its legs
+func routeStops(route *types.Route) int {
+	return max(len(route.Legs)-1, 0)
+}
+
+// roundDiv divides two non-negative integers, rounding half-up
+func roundDiv(num, den int64) int64 {
+	return (num + den/2) / den
+}
@@ -0,0 +1,142 @@
+package osrm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+var testPricing = types.PackagePricing{
+	BaseFeeCents:     200,
+	PerKmCents:       150,
+	PerMinuteCents:   25,
+	MinimumFareCents: 500,
+	BookingFeeCents:  150,
+	StopFeeCents:     100,
+}
+
+func TestPriceRoute(t *testing.T) {
+	noMinimum := testPricing
+	noMinimum.MinimumFareCents = 0
+
+	noBookingFee := testPricing
+	noBookingFee.BookingFeeCents = 0
+
+	tests := []struct {
+		name    string
+		pricing types.PackagePricing
+		meters  float64
+		seconds float64
+		stops   int
+		surge   float64
+		want    string
+	}{
+		{
+			name:    "above minimum fare",
+			pricing: testPricing,
+			meters:  10000, seconds: 600, surge: 1,
+			want: "base_fee=200 distance=1500 time=250 booking_fee=150",
+		},
+		{
+			name:    "minimum fare adjustment",
+			pricing: testPricing,
+			meters:  1000, seconds: 60, surge: 1,
+			want: "base_fee=200 distance=150 time=25 minimum_fare_adjustment=125 booking_fee=150",
+		},
+		{
+			name:    "surge applies to the minimum fare but not the booking fee",
+			pricing: testPricing,
+			meters:  1000, seconds: 60, surge: 1.5,
+			want: "base_fee=200 distance=150 time=25 minimum_fare_adjustment=125 surge=250 booking_fee=150",
+		},
+		{
+			name:    "surge rounded to whole percent",
+			pricing: testPricing,
+			meters:  10000, seconds: 600, surge: 1.234, // 23% of 1950 is 448.5
+			want: "base_fee=200 distance=1500 time=250 surge=449 booking_fee=150",
+		},
+		{
+			name:    "no surge at 1",
+			pricing: testPricing,
+			meters:  10000, seconds: 600, surge: 1.004,
+			want: "base_fee=200 distance=1500 time=250 booking_fee=150",
+		},
+		{
+			name:    "no discount below 1",
+			pricing: testPricing,
+			meters:  10000, seconds: 600, surge: 0.8,
+			want: "base_fee=200 distance=1500 time=250 booking_fee=150",
+		},
+		{
+			name:    "distance and time rounded half-up",
+			pricing: noMinimum,
+			meters:  1010, seconds: 30, surge: 1, // 151.5 and 12.5 cents
+			want: "base_fee=200 distance=152 time=13 booking_fee=150",
+		},
+		{
+			name:    "distance and time rounded down below half",
+			pricing: noMinimum,
+			meters:  1003, seconds: 29, surge: 1, // 150.45 and 12.08 cents
+			want: "base_fee=200 distance=150 time=12 booking_fee=150",
+		},
+		{
+			name:    "fractional meters and seconds",
+			pricing: noMinimum,
+			meters:  999.6, seconds: 59.5, surge: 1,
+			want: "base_fee=200 distance=150 time=25 booking_fee=150",
+		},
+		{
+			name:    "stop fee per intermediate stop",
+			pricing: testPricing,
+			meters:  10000, seconds: 600, stops: 2, surge: 1,
+			want: "base_fee=200 distance=1500 time=250 stop_fee=200 booking_fee=150",
+		},
+		{
+			name:    "no booking fee",
+			pricing: noBookingFee,
+			meters:  10000, seconds: 600, surge: 1,
+			want: "base_fee=200 distance=1500 time=250",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &types.Route{Distance: tt.meters, Duration: tt.seconds}
+			if got := formatLineItems(PriceRoute(tt.pricing, route, tt.stops, tt.surge)); got != tt.want {
+				t.Errorf("PriceRoute = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundDiv(t *testing.T) {
+	tests := []struct {
+		num, den, want int64
+	}{
+		{0, 100, 0},
+		{49, 100, 0},
+		{50, 100, 1},
+		{149, 100, 1},
+		{150, 100, 2},
+		{5, 2, 3},
+		{7, 3, 2},
+		{8, 3, 3},
+		{300, 60, 5},
+	}
+
+	for _, tt := range tests {
+		if got := roundDiv(tt.num, tt.den); got != tt.want {
+			t.Errorf("roundDiv(%d, %d) = %d, want %d", tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func formatLineItems(items []*types.FareLineItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%s=%d", item.Type, item.AmountInCents))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mmcloughlin/geohash"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/util"
+)
+
+const (
+	// driverGeohashPrecision matches the precision used by the driver app
+	driverGeohashPrecision = 7
+
+	// cellPrecision is the geohash length used to bucket drivers (~4.9km x 4.9km cells)
+	cellPrecision = 5
+
+	// driverStaleAfter is how long a driver stays a candidate without sending a location update
+	driverStaleAfter = 2 * time.Minute
+)
+
+// driverDocument is the MongoDB representation of a driver
+type driverDocument struct {
+	types.Driver `bson:",inline"`
+	Cell         string    `bson:"cell"`
+	Available    bool      `bson:"available"`
+	UpdatedAt    time.Time `bson:"updated_at"`
+}
+
+// MongoDriverRepository implements DriverRepository using MongoDB
+type MongoDriverRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoDriverRepository creates a new MongoDB driver repository
+func NewMongoDriverRepository(db *mongo.Database) domain.DriverRepository {
+	collection := db.Collection("drivers")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "cell", Value: 1},
+				{Key: "available", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoDriverRepository{
+		collection: collection,
+	}
+}
+
+// Upsert stores the latest known state of a driver
+func (r *MongoDriverRepository) Upsert(ctx context.Context, driver *types.Driver) error {
+	if driver.Location != nil {
+		driver.Geohash = geohash.EncodeWithPrecision(driver.Location.Latitude, driver.Location.Longitude, driverGeohashPrecision)
+	}
+
+	set := bson.M{
+		"name":            driver.Name,
+		"location":        driver.Location,
+		"geohash":         driver.Geohash,
+		"profile_picture": driver.ProfilePicture,
+		"car_plate":       driver.CarPlate,
+		"package_slug":    driver.PackageSlug,
+		"updated_at":      time.Now(),
+	}
+	if len(driver.Geohash) >= cellPrecision {
+		set["cell"] = driver.Geohash[:cellPrecision]
+	}
+
+	filter := bson.M{"_id": driver.ID}
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": bson.M{"available": true},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// GetByID retrieves a driver by its ID
+func (r *MongoDriverRepository) GetByID(ctx context.Context, id string) (*types.Driver, error) {
+	var doc driverDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc.Driver, nil
+}
+
+// FindCandidates returns available drivers near the pickup, closest first
+func (r *MongoDriverRepository) FindCandidates(ctx context.Context, pickup *types.Coordinate, packageSlug types.CarPackageSlug, exclude []string, limit int) ([]*types.Driver, error) {
+	filter := bson.M{
+		"cell":       bson.M{"$in": searchCells(pickup)},
+		"available":  true,
+		"updated_at": bson.M{"$gte": time.Now().Add(-driverStaleAfter)},
+	}
+	if packageSlug != "" {
+		filter["package_slug"] = packageSlug
+	}
+	if len(exclude) > 0 {
+		filter["_id"] = bson.M{"$nin": exclude}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []driverDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	distance := func(d *types.Driver) float64 {
+		return util.HaversineDistance(pickup.Latitude, pickup.Longitude, d.Location.Latitude, d.Location.Longitude)
+	}
+
+	drivers := make([]*types.Driver, 0, len(docs))
+	for i := range docs {
+		if docs[i].Location != nil {
+			drivers = append(drivers, &docs[i].Driver)
+		}
+	}
+
+	sort.Slice(drivers, func(i, j int) bool {
+		return distance(drivers[i]) < distance(drivers[j])
+	})
+
+	if limit > 0 && len(drivers) > limit {
+		drivers = drivers[:limit]
+	}
+	return drivers, nil
+}
+
+// CountAvailable returns how many available, recently seen drivers are near the pickup,
+// searching the same cells as FindCandidates
+func (r *MongoDriverRepository) CountAvailable(ctx context.Context, pickup *types.Coordinate) (int64, error) {
+	filter := bson.M{
+		"cell":       bson.M{"$in": searchCells(pickup)},
+		"available":  true,
+		"updated_at": bson.M{"$gte": time.Now().Add(-driverStaleAfter)},
+	}
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// SetAvailable marks whether a driver can receive new trip offers
+func (r *MongoDriverRepository) SetAvailable(ctx context.Context, driverID string, available bool) error {
+	filter := bson.M{"_id": driverID}
+	update := bson.M{
+		"$set": bson.M{
+			"available": available,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// searchCells returns the cell of the pickup and the eight around it, so drivers just across a cell
+// border are found too
+func searchCells(pickup *types.Coordinate) []string {
+	cell := geohash.EncodeWithPrecision(pickup.Latitude, pickup.Longitude, cellPrecision)
+	return append(geohash.Neighbors(cell), cell)
+}
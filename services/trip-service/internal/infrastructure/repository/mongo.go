@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -19,7 +20,7 @@ type MongoTripRepository struct {
 // NewMongoTripRepository creates a new MongoDB trip repository
 func NewMongoTripRepository(db *mongo.Database) domain.TripRepository {
 	collection := db.Collection("trips")
-	
+
 	// Create indexes
 	indexes := []mongo.IndexModel{
 		{
@@ -31,10 +32,54 @@ func NewMongoTripRepository(db *mongo.Database) domain.TripRepository {
 		{
 			Keys: bson.D{{Key: "created_at", Value: -1}},
 		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "dispatch_attempts.expires_at", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "driver._id", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "schedule.dispatch_at", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "schedule.reminder_at", Value: 1},
+			},
+		},
+		{
+			// A quoted fare can only be booked once
+			Keys:    bson.D{{Key: "selected_fare._id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			// Neither can a fare booked into a pool trip
+			Keys:    bson.D{{Key: "riders.fare_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "selected_fare.package_slug", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "riders.user_id", Value: 1}},
+		},
 	}
-	
+
 	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
-	
+
 	return &MongoTripRepository{
 		collection: collection,
 	}
@@ -45,8 +90,11 @@ func (r *MongoTripRepository) Create(ctx context.Context, trip *types.Trip) erro
 	now := time.Now()
 	trip.CreatedAt = now
 	trip.UpdatedAt = now
-	
+
 	_, err := r.collection.InsertOne(ctx, trip)
+	if mongo.IsDuplicateKeyError(err) && trip.SelectedFare != nil {
+		return fmt.Errorf("%w: %s", domain.ErrFareAlreadyBooked, trip.SelectedFare.ID)
+	}
 	return err
 }
 
@@ -63,28 +111,247 @@ func (r *MongoTripRepository) GetByID(ctx context.Context, id string) (*types.Tr
 	return &trip, nil
 }
 
-// Update updates an existing trip
-func (r *MongoTripRepository) Update(ctx context.Context, trip *types.Trip) error {
+// Update updates an existing trip if it is still in the expected status and has the same riders
+func (r *MongoTripRepository) Update(ctx context.Context, trip *types.Trip, expected types.TripStatus) error {
+	return r.replace(ctx, trip, expected, len(trip.Riders))
+}
+
+// AddPoolRider stores a pool trip the last rider was just added to, if no other rider joined meanwhile
+func (r *MongoTripRepository) AddPoolRider(ctx context.Context, trip *types.Trip, expected types.TripStatus) error {
+	err := r.replace(ctx, trip, expected, len(trip.Riders)-1)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s", domain.ErrFareAlreadyBooked, trip.Riders[len(trip.Riders)-1].FareID)
+	}
+	return err
+}
+
+// replace overwrites a trip that is still in the expected status and has the expected number of riders.
+// Guarding on the riders keeps a rider joining a pool trip from being lost to a concurrent update.
+// The trace is left alone: AppendTrace pushes to it concurrently, partitioned by driver rather than trip.
+func (r *MongoTripRepository) replace(ctx context.Context, trip *types.Trip, expected types.TripStatus, riders int) error {
 	trip.UpdatedAt = time.Now()
-	
-	filter := bson.M{"_id": trip.ID}
-	update := bson.M{"$set": trip}
-	
+
+	fields, err := tripFields(trip)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": trip.ID, "status": expected}
+	if riders > 0 {
+		filter["riders"] = bson.M{"$size": riders}
+	}
+	update := bson.M{"$set": fields}
+
 	opts := options.Update().SetUpsert(false)
-	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	result, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return r.noMatchError(ctx, trip.ID)
+	}
+	return nil
 }
 
-// UpdateStatus updates only the status of a trip
-func (r *MongoTripRepository) UpdateStatus(ctx context.Context, id string, status types.TripStatus) error {
-	filter := bson.M{"_id": id}
+// UpdateStatus moves a trip from one status to another using compare-and-set
+func (r *MongoTripRepository) UpdateStatus(ctx context.Context, id string, from, to types.TripStatus) error {
+	if err := domain.ValidateTransition(from, to); err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": id, "status": from}
 	update := bson.M{
 		"$set": bson.M{
-			"status":     status,
+			"status":     to,
 			"updated_at": time.Now(),
 		},
 	}
-	
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return r.noMatchError(ctx, id)
+	}
+	return nil
+}
+
+// FindExpiredOffers returns trips whose outstanding driver offer lapsed before the given time
+func (r *MongoTripRepository) FindExpiredOffers(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error) {
+	filter := bson.M{
+		"status": types.TripStatusDriverFound,
+		"dispatch_attempts": bson.M{
+			"$elemMatch": bson.M{
+				"outcome":    types.DispatchOutcomePending,
+				"expires_at": bson.M{"$lte": before},
+			},
+		},
+	}
+
+	opts := options.Find().SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var trips []*types.Trip
+	if err := cursor.All(ctx, &trips); err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+// FindUndispatched returns trips still waiting for a driver offer that were last updated before the given time
+func (r *MongoTripRepository) FindUndispatched(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error) {
+	filter := bson.M{
+		"status":     types.TripStatusCreated,
+		"updated_at": bson.M{"$lte": before},
+	}
+
+	return r.find(ctx, filter, "updated_at", limit)
+}
+
+// HasTrips reports whether a user ever booked a trip that was not cancelled
+func (r *MongoTripRepository) HasTrips(ctx context.Context, userID string) (bool, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"user_id": userID},
+			bson.M{"riders": bson.M{"$elemMatch": bson.M{"user_id": userID, "cancellation": bson.M{"$exists": false}}}},
+		},
+		"status": bson.M{"$ne": types.TripStatusCancelled},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindPoolTrips returns the most recent pool trips in one of the given statuses
+func (r *MongoTripRepository) FindPoolTrips(ctx context.Context, statuses []types.TripStatus, limit int) ([]*types.Trip, error) {
+	filter := bson.M{
+		"selected_fare.package_slug": types.CarPackagePool,
+		"status":                     bson.M{"$in": statuses},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var trips []*types.Trip
+	if err := cursor.All(ctx, &trips); err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+// FindDueScheduled returns scheduled trips whose dispatch time is before the given time
+func (r *MongoTripRepository) FindDueScheduled(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error) {
+	filter := bson.M{
+		"status":               types.TripStatusScheduled,
+		"schedule.dispatch_at": bson.M{"$lte": before},
+	}
+
+	return r.find(ctx, filter, "schedule.dispatch_at", limit)
+}
+
+// FindDueReminders returns scheduled trips whose reminder is due before the given time and not sent yet
+func (r *MongoTripRepository) FindDueReminders(ctx context.Context, before time.Time, limit int) ([]*types.Trip, error) {
+	filter := bson.M{
+		"status":                    types.TripStatusScheduled,
+		"schedule.reminder_at":      bson.M{"$lte": before},
+		"schedule.reminder_sent_at": bson.M{"$exists": false},
+	}
+
+	return r.find(ctx, filter, "schedule.reminder_at", limit)
+}
+
+// MarkReminderSent records that a trip's reminder was sent, unless it already was
+func (r *MongoTripRepository) MarkReminderSent(ctx context.Context, id string, at time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":                       id,
+		"schedule.reminder_sent_at": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{"schedule.reminder_sent_at": at},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// find returns the trips matching the filter, oldest first by the sort key
+func (r *MongoTripRepository) find(ctx context.Context, filter bson.M, sortKey string, limit int) ([]*types.Trip, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortKey, Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var trips []*types.Trip
+	if err := cursor.All(ctx, &trips); err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+// AppendTrace records a location of a driver on the trip they are driving, if any
+func (r *MongoTripRepository) AppendTrace(ctx context.Context, driverID string, point *types.TracePoint) error {
+	filter := bson.M{
+		"driver._id": driverID,
+		"status":     types.TripStatusInProgress,
+	}
+	update := bson.M{
+		"$push": bson.M{"trace": point},
+	}
+
 	_, err := r.collection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// tripFields returns the fields of a trip to $set, without its ID and trace
+func tripFields(trip *types.Trip) (bson.M, error) {
+	raw, err := bson.Marshal(trip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trip: %w", err)
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trip: %w", err)
+	}
+	delete(fields, "_id")
+	delete(fields, "trace")
+	return fields, nil
+}
+
+// noMatchError tells apart a missing trip from one whose status has moved on
+func (r *MongoTripRepository) noMatchError(ctx context.Context, id string) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return domain.ErrTripNotFound
+	}
+	return domain.ErrStatusConflict
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// outboxRetention is how long published and failed messages are kept for troubleshooting
+const outboxRetention = 7 * 24 * time.Hour
+
+// MongoOutboxRepository implements OutboxRepository using MongoDB
+type MongoOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoOutboxRepository creates a new MongoDB outbox repository
+func NewMongoOutboxRepository(db *mongo.Database) domain.OutboxRepository {
+	collection := db.Collection("outbox")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "sent_at", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+		},
+		{
+			Keys:    bson.D{{Key: "failed_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoOutboxRepository{
+		collection: collection,
+	}
+}
+
+// Add stores an event, as part of the transaction of the context if there is one
+func (r *MongoOutboxRepository) Add(ctx context.Context, msg *types.OutboxMessage) error {
+	_, err := r.collection.InsertOne(ctx, msg)
+	return err
+}
+
+// ClaimPending locks up to limit unsent messages, oldest first.
+// Each message is claimed with its own conditional update, so concurrent relays never share one.
+// Once a message of a trip is locked by another relay, or could not be claimed, the later messages of
+// that trip are left alone, so they are never published ahead of it.
+func (r *MongoOutboxRepository) ClaimPending(ctx context.Context, lease time.Duration, limit int) ([]*types.OutboxMessage, error) {
+	pending := bson.M{
+		"sent_at":   bson.M{"$exists": false},
+		"failed_at": bson.M{"$exists": false},
+	}
+	cursor, err := r.collection.Find(ctx, pending, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"locked_until": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var messages []*types.OutboxMessage
+	blocked := make(map[string]bool) // trips with an older message this relay does not hold
+	for len(messages) < limit && cursor.Next(ctx) {
+		var candidate types.OutboxMessage
+		if err := cursor.Decode(&candidate); err != nil {
+			return messages, err
+		}
+		if candidate.AggregateID != "" && blocked[candidate.AggregateID] {
+			continue
+		}
+
+		filter := bson.M{
+			"_id":       candidate.ID,
+			"sent_at":   bson.M{"$exists": false},
+			"failed_at": bson.M{"$exists": false},
+			"$or": bson.A{
+				bson.M{"locked_until": bson.M{"$exists": false}},
+				bson.M{"locked_until": bson.M{"$lte": now}},
+			},
+		}
+		var msg types.OutboxMessage
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&msg)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// Locked by another relay, or published or failed since it was read
+			if candidate.AggregateID != "" {
+				blocked[candidate.AggregateID] = true
+			}
+			continue
+		}
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, cursor.Err()
+}
+
+// Release ends the lease of claimed messages that were not published
+func (r *MongoOutboxRepository) Release(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"locked_until": ""}})
+	return err
+}
+
+// MarkSent records that a message was confirmed by the broker
+func (r *MongoOutboxRepository) MarkSent(ctx context.Context, id string, at time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"sent_at": at},
+		"$unset": bson.M{"locked_until": ""},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// MarkFailed records that a message will never be published, and why
+func (r *MongoOutboxRepository) MarkFailed(ctx context.Context, id string, at time.Time, reason string) error {
+	update := bson.M{
+		"$set":   bson.M{"failed_at": at, "error": reason},
+		"$unset": bson.M{"locked_until": ""},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// MongoPricingRepository implements PricingRepository using MongoDB.
+// Every document of the pricing_rules collection is one immutable version of a region's rates.
+type MongoPricingRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoPricingRepository creates a new MongoDB pricing repository
+func NewMongoPricingRepository(db *mongo.Database) domain.PricingRepository {
+	collection := db.Collection("pricing_rules")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "region", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoPricingRepository{
+		collection: collection,
+	}
+}
+
+// ListLatest returns the newest pricing table of every region
+func (r *MongoPricingRepository) ListLatest(ctx context.Context) ([]*types.PricingTable, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "region", Value: 1}, {Key: "version", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$region"},
+			{Key: "latest", Value: bson.D{{Key: "$first", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$latest"}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tables []*types.PricingTable
+	if err := cursor.All(ctx, &tables); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// GetVersion returns a specific pricing table version of a region
+func (r *MongoPricingRepository) GetVersion(ctx context.Context, region string, version int64) (*types.PricingTable, error) {
+	var table types.PricingTable
+	err := r.collection.FindOne(ctx, bson.M{"region": region, "version": version}).Decode(&table)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &table, nil
+}
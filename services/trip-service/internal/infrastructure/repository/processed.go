@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// processedMessageRetention is how long handled message IDs are remembered.
+// It must outlast any redelivery, including outbox messages published again after a relay restart.
+const processedMessageRetention = 7 * 24 * time.Hour
+
+// MongoProcessedMessageRepository implements ProcessedMessageRepository using MongoDB
+type MongoProcessedMessageRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoProcessedMessageRepository creates a new MongoDB processed message repository
+func NewMongoProcessedMessageRepository(db *mongo.Database) domain.ProcessedMessageRepository {
+	collection := db.Collection("processed_messages")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "processed_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(processedMessageRetention.Seconds())),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoProcessedMessageRepository{
+		collection: collection,
+	}
+}
+
+// IsProcessed reports whether a consumer already handled the message with the given ID
+func (r *MongoProcessedMessageRepository) IsProcessed(ctx context.Context, consumer, messageID string) (bool, error) {
+	err := r.collection.FindOne(ctx, bson.M{"_id": processedMessageKey(consumer, messageID)}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MarkProcessed records that a consumer handled the message with the given ID.
+// Recording the same message twice keeps the first time it was handled.
+func (r *MongoProcessedMessageRepository) MarkProcessed(ctx context.Context, consumer, messageID string, at time.Time) error {
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"consumer":     consumer,
+			"message_id":   messageID,
+			"processed_at": at,
+		},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": processedMessageKey(consumer, messageID)}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// processedMessageKey scopes a message ID to a consumer, so several consumers can each handle the same message
+func processedMessageKey(consumer, messageID string) string {
+	return consumer + ":" + messageID
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// MongoPromotionRepository implements PromotionRepository using MongoDB.
+// Limits are enforced with conditional updates on counters, so concurrent redemptions
+// can never exceed them:
+//   - promotions.redemptions counts every redemption of a code
+//   - promo_usage holds one counter per code and user
+//   - promo_redemptions keeps one record per trip for auditing, and at most one first ride
+//     redemption per user
+type MongoPromotionRepository struct {
+	promotions  *mongo.Collection
+	usage       *mongo.Collection
+	redemptions *mongo.Collection
+}
+
+// NewMongoPromotionRepository creates a new MongoDB promotion repository
+func NewMongoPromotionRepository(db *mongo.Database) domain.PromotionRepository {
+	redemptions := db.Collection("promo_redemptions")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "code", Value: 1}, {Key: "user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().
+				SetName("first_ride_user_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"first_ride": true}),
+		},
+	}
+
+	_, _ = redemptions.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoPromotionRepository{
+		promotions:  db.Collection("promotions"),
+		usage:       db.Collection("promo_usage"),
+		redemptions: redemptions,
+	}
+}
+
+// GetByCode retrieves a promotion by its code
+func (r *MongoPromotionRepository) GetByCode(ctx context.Context, code string) (*types.Promotion, error) {
+	var promo types.Promotion
+	err := r.promotions.FindOne(ctx, bson.M{"_id": code}).Decode(&promo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &promo, nil
+}
+
+// CountUserRedemptions returns how often a user redeemed a promo code
+func (r *MongoPromotionRepository) CountUserRedemptions(ctx context.Context, code, userID string) (int64, error) {
+	var usage struct {
+		Count int64 `bson:"count"`
+	}
+	err := r.usage.FindOne(ctx, bson.M{"_id": usageID(code, userID)}).Decode(&usage)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return usage.Count, nil
+}
+
+// Redeem records a redemption, atomically enforcing the per-user and global limits.
+// It is meant to run in the transaction creating the trip, which undoes it if the trip is not created.
+func (r *MongoPromotionRepository) Redeem(ctx context.Context, promo *types.Promotion, redemption *types.PromoRedemption) error {
+	redemption.FirstRide = promo.FirstRideOnly
+	redemption.RedeemedAt = time.Now()
+
+	// Per-user limit: the upsert only matches while the counter is below the limit,
+	// so an exhausted counter makes it try to insert a duplicate _id instead
+	if promo.PerUserLimit > 0 {
+		filter := bson.M{
+			"_id":   usageID(promo.Code, redemption.UserID),
+			"count": bson.M{"$lt": promo.PerUserLimit},
+		}
+		update := bson.M{
+			"$inc":         bson.M{"count": 1},
+			"$setOnInsert": bson.M{"code": promo.Code, "user_id": redemption.UserID},
+		}
+
+		_, err := r.usage.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", domain.ErrPromoLimitReached, promo.Code)
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := r.incUsage(ctx, promo.Code, redemption.UserID); err != nil {
+			return err
+		}
+	}
+
+	// Global limit
+	filter := bson.M{"_id": promo.Code}
+	if promo.GlobalLimit > 0 {
+		filter["redemptions"] = bson.M{"$lt": promo.GlobalLimit}
+	}
+
+	result, err := r.promotions.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"redemptions": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrPromoLimitReached, promo.Code)
+	}
+
+	// First ride promotions: the partial unique index on user_id admits one such record per user,
+	// whatever trips are being booked at the same time
+	_, err = r.redemptions.InsertOne(ctx, redemption)
+	if promo.FirstRideOnly && mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %s is only valid on the first ride", domain.ErrPromoNotApplicable, promo.Code)
+	}
+	return err
+}
+
+func (r *MongoPromotionRepository) incUsage(ctx context.Context, code, userID string) error {
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$setOnInsert": bson.M{"code": code, "user_id": userID},
+	}
+
+	_, err := r.usage.UpdateOne(ctx, bson.M{"_id": usageID(code, userID)}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func usageID(code, userID string) string {
+	return code + ":" + userID
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// MongoRideFareRepository implements RideFareRepository using MongoDB
+type MongoRideFareRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRideFareRepository creates a new MongoDB ride fare repository
+func NewMongoRideFareRepository(db *mongo.Database) domain.RideFareRepository {
+	collection := db.Collection("ride_fares")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			// Let MongoDB drop quotes once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoRideFareRepository{
+		collection: collection,
+	}
+}
+
+// SaveMany stores the fares quoted by a trip preview
+func (r *MongoRideFareRepository) SaveMany(ctx context.Context, fares []*types.RouteFare) error {
+	if len(fares) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(fares))
+	for _, fare := range fares {
+		docs = append(docs, fare)
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// GetByID retrieves a quoted fare by its ID
+func (r *MongoRideFareRepository) GetByID(ctx context.Context, id string) (*types.RouteFare, error) {
+	var fare types.RouteFare
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&fare)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &fare, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// demandRetention is how long demand records are kept; it must exceed any surge window
+const demandRetention = time.Hour
+
+// MongoSurgeRepository implements SurgeRepository using MongoDB.
+// Demand is stored as one document per rider and cell, so repeated previews count once.
+type MongoSurgeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSurgeRepository creates a new MongoDB surge repository
+func NewMongoSurgeRepository(db *mongo.Database) domain.SurgeRepository {
+	collection := db.Collection("surge_demand")
+
+	// Create indexes
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "cell", Value: 1}, {Key: "seen_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "seen_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(demandRetention.Seconds())),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), indexes)
+
+	return &MongoSurgeRepository{
+		collection: collection,
+	}
+}
+
+// RecordDemand notes that a rider is looking for a ride in a cell
+func (r *MongoSurgeRepository) RecordDemand(ctx context.Context, cell, userID string) error {
+	filter := bson.M{"_id": cell + ":" + userID}
+	update := bson.M{
+		"$set": bson.M{
+			"cell":    cell,
+			"user_id": userID,
+			"seen_at": time.Now(),
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// CountDemand returns how many distinct riders looked for a ride in a cell since the given time
+func (r *MongoSurgeRepository) CountDemand(ctx context.Context, cell string, since time.Time) (int64, error) {
+	filter := bson.M{
+		"cell":    cell,
+		"seen_at": bson.M{"$gte": since},
+	}
+	return r.collection.CountDocuments(ctx, filter)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// MongoTransactor implements Transactor with MongoDB multi-document transactions.
+// Transactions need MongoDB to run as a replica set; a single node one is enough.
+type MongoTransactor struct {
+	client *mongo.Client
+}
+
+// NewMongoTransactor creates a new MongoDB transactor
+func NewMongoTransactor(client *mongo.Client) domain.Transactor {
+	return &MongoTransactor{
+		client: client,
+	}
+}
+
+// WithinTransaction runs fn in a transaction, retrying it on transient errors as the driver recommends.
+// The session travels in the context, so repository calls made with it take part in the transaction.
+func (t *MongoTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Already in a transaction: join it
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
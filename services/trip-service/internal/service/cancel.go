@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// CancelTrip cancels a trip on behalf of its rider or assigned driver and returns the cancellation made.
+// Riders are charged the cancellation fee once a driver has been assigned for longer than the free period.
+func (s *TripServiceImpl) CancelTrip(ctx context.Context, tripID string, actorID string, actor types.TripActor, reason string) (*types.Trip, *types.Cancellation, error) {
+	trip, err := s.getTrip(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isParticipant(trip, actorID, actor) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrNotTripParticipant, actorID)
+	}
+
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusCancelled); err != nil {
+		return nil, nil, err
+	}
+
+	// The trip goes on for the other riders of a pool, or is settled for those already dropped off
+	if actor == types.TripActorRider && len(remainingPoolRiders(trip)) > 1 {
+		return s.leavePool(ctx, trip, poolRider(trip, actorID), reason)
+	}
+
+	now := time.Now()
+	trip.Status = types.TripStatusCancelled
+	trip.Cancellation = &types.Cancellation{
+		CancelledBy: actor,
+		ActorID:     actorID,
+		Reason:      reason,
+		FeeInCents:  s.cancellationFee(trip, actor, now),
+		CancelledAt: now,
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to cancel trip: %w", err)
+		}
+
+		// Release the assigned driver so they can be offered new trips
+		if trip.Driver != nil {
+			if err := s.driverRepo.SetAvailable(ctx, trip.Driver.ID, true); err != nil {
+				return fmt.Errorf("failed to release driver: %w", err)
+			}
+		}
+
+		if err := s.eventPublisher.PublishTripCancelled(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish trip cancelled event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return trip, trip.Cancellation, nil
+}
+
+// cancellationFee applies the cancellation policy to a trip being cancelled at the given time
+func (s *TripServiceImpl) cancellationFee(trip *types.Trip, actor types.TripActor, at time.Time) int64 {
+	// Drivers backing out never cost the rider anything
+	if actor != types.TripActorRider || trip.DriverAssignedAt == nil {
+		return 0
+	}
+
+	if at.Sub(*trip.DriverAssignedAt) <= s.cfg.CancellationFreePeriod {
+		return 0
+	}
+
+	return s.cfg.CancellationFeeInCents
+}
+
+// isParticipant reports whether the user is the trip's rider, one of its pool riders or its assigned driver, as claimed
+func isParticipant(trip *types.Trip, userID string, actor types.TripActor) bool {
+	switch actor {
+	case types.TripActorRider:
+		if isPool(trip) {
+			// Riders already dropped off are done with the trip
+			rider := poolRider(trip, userID)
+			return rider != nil && rider.DroppedOffAt == nil
+		}
+		return trip.UserID == userID
+	case types.TripActorDriver:
+		return trip.Driver != nil && trip.Driver.ID == userID
+	default:
+		return false
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// noDriversFoundReason is the cancellation reason of trips nobody could take
+const noDriversFoundReason = "no_drivers_found"
+
+// dispatchNext offers the trip to the closest available driver who has not been offered it yet.
+// Once the attempts are exhausted or no candidate is left, the trip is given up on.
+// A trip left in created by a failure here is dispatched again by RedispatchStalled.
+func (s *TripServiceImpl) dispatchNext(ctx context.Context, trip *types.Trip) error {
+	if len(trip.DispatchAttempts) >= s.cfg.MaxDispatchAttempts {
+		return s.noDriversFound(ctx, trip)
+	}
+
+	if trip.Pickup == nil {
+		return fmt.Errorf("trip %s has no pickup location", trip.ID)
+	}
+
+	var packageSlug types.CarPackageSlug
+	if trip.SelectedFare != nil {
+		packageSlug = trip.SelectedFare.PackageSlug
+	}
+	if packageSlug == types.CarPackagePool {
+		packageSlug = types.CarPackageSedan
+	}
+
+	candidates, err := s.driverRepo.FindCandidates(ctx, trip.Pickup, packageSlug, offeredDriverIDs(trip), 1)
+	if err != nil {
+		return fmt.Errorf("failed to find candidate drivers: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		return s.noDriversFound(ctx, trip)
+	}
+
+	driver := candidates[0]
+
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusDriverFound); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	offer := &types.DispatchAttempt{
+		DriverID:  driver.ID,
+		Outcome:   types.DispatchOutcomePending,
+		OfferedAt: now,
+		ExpiresAt: now.Add(s.cfg.OfferTimeout),
+	}
+
+	trip.Status = types.TripStatusDriverFound
+	trip.DispatchAttempts = append(trip.DispatchAttempts, offer)
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to record dispatch attempt: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishDriverTripRequest(ctx, trip, offer); err != nil {
+			return fmt.Errorf("failed to publish trip request: %w", err)
+		}
+		return nil
+	})
+}
+
+// acceptTrip assigns the offered driver to the trip
+func (s *TripServiceImpl) acceptTrip(ctx context.Context, trip *types.Trip, driverID string) error {
+	attempt, err := pendingAttempt(trip, driverID)
+	if err != nil {
+		return err
+	}
+
+	if time.Now().After(attempt.ExpiresAt) {
+		return fmt.Errorf("%w: %s", domain.ErrOfferExpired, driverID)
+	}
+
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusDriverAssigned); err != nil {
+		return err
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return fmt.Errorf("failed to get driver: %w", err)
+	}
+	if driver == nil {
+		driver = &types.Driver{ID: driverID}
+	}
+
+	now := time.Now()
+	attempt.Outcome = types.DispatchOutcomeAccepted
+	attempt.RespondedAt = &now
+	trip.Status = types.TripStatusDriverAssigned
+	trip.Driver = driver
+	trip.DriverAssignedAt = &now
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to assign driver: %w", err)
+		}
+
+		if err := s.driverRepo.SetAvailable(ctx, driverID, false); err != nil {
+			return fmt.Errorf("failed to mark driver unavailable: %w", err)
+		}
+
+		// Publish driver assigned event
+		if err := s.eventPublisher.PublishDriverAssigned(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish driver assigned event: %w", err)
+		}
+		return nil
+	})
+}
+
+// declineTrip records the decline and moves on to the next candidate driver
+func (s *TripServiceImpl) declineTrip(ctx context.Context, trip *types.Trip, driverID string) error {
+	attempt, err := pendingAttempt(trip, driverID)
+	if err != nil {
+		return err
+	}
+
+	return s.releaseOffer(ctx, trip, attempt, types.DispatchOutcomeDeclined)
+}
+
+// expiredOfferBatchSize bounds how many lapsed offers are handled per sweep
+const expiredOfferBatchSize = 100
+
+// ExpireOffers treats every lapsed driver offer as a decline and dispatches the trip again.
+// Offers live on the trip document, so deadlines survive restarts and any replica can sweep them.
+func (s *TripServiceImpl) ExpireOffers(ctx context.Context) error {
+	trips, err := s.repo.FindExpiredOffers(ctx, time.Now(), expiredOfferBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find expired offers: %w", err)
+	}
+
+	for _, trip := range trips {
+		attempt := trip.DispatchAttempts[len(trip.DispatchAttempts)-1]
+		if attempt.Outcome != types.DispatchOutcomePending {
+			continue
+		}
+
+		err := s.releaseOffer(ctx, trip, attempt, types.DispatchOutcomeExpired)
+		if errors.Is(err, domain.ErrStatusConflict) {
+			// The driver answered or another replica got there first
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to expire offer for trip %s: %v", trip.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// releaseOffer closes the outstanding offer with the given outcome and dispatches the trip again
+func (s *TripServiceImpl) releaseOffer(ctx context.Context, trip *types.Trip, attempt *types.DispatchAttempt, outcome types.DispatchOutcome) error {
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusCreated); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	attempt.Outcome = outcome
+	attempt.RespondedAt = &now
+	trip.Status = types.TripStatusCreated
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to release offer: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishDriverNotInterested(ctx, trip, attempt.DriverID); err != nil {
+			return fmt.Errorf("failed to publish driver not interested event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The decline is recorded either way; a failed dispatch is picked up by RedispatchStalled
+	if err := s.dispatchNext(ctx, trip); err != nil {
+		log.Printf("Failed to dispatch trip %s again, leaving it to the sweep: %v", trip.ID, err)
+	}
+	return nil
+}
+
+// undispatchedBatchSize bounds how many stalled trips are dispatched per sweep
+const undispatchedBatchSize = 100
+
+// RedispatchStalled dispatches the trips that have been waiting for a driver offer for longer than
+// RedispatchAfter. They are left behind when dispatching fails after the trip was saved, e.g. because the
+// drivers could not be looked up, and nothing else would ever offer them again.
+func (s *TripServiceImpl) RedispatchStalled(ctx context.Context) error {
+	trips, err := s.repo.FindUndispatched(ctx, time.Now().Add(-s.cfg.RedispatchAfter), undispatchedBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find undispatched trips: %w", err)
+	}
+
+	for _, trip := range trips {
+		err := s.dispatchNext(ctx, trip)
+		if errors.Is(err, domain.ErrStatusConflict) {
+			// Dispatched or cancelled meanwhile
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to dispatch stalled trip %s: %v", trip.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// noDriversFound gives up on the trip: it is cancelled by the system, so it is never dispatched again,
+// and the rider is told that nobody could take it
+func (s *TripServiceImpl) noDriversFound(ctx context.Context, trip *types.Trip) error {
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusCancelled); err != nil {
+		return err
+	}
+
+	trip.Status = types.TripStatusCancelled
+	trip.Cancellation = &types.Cancellation{
+		CancelledBy: types.TripActorSystem,
+		Reason:      noDriversFoundReason,
+		CancelledAt: time.Now(),
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to give up on trip: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishNoDriversFound(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish no drivers found event: %w", err)
+		}
+		return nil
+	})
+}
+
+// pendingAttempt returns the outstanding offer of the trip, which must belong to the driver
+func pendingAttempt(trip *types.Trip, driverID string) (*types.DispatchAttempt, error) {
+	if len(trip.DispatchAttempts) == 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrDriverNotOffered, driverID)
+	}
+
+	attempt := trip.DispatchAttempts[len(trip.DispatchAttempts)-1]
+	if attempt.DriverID != driverID || attempt.Outcome != types.DispatchOutcomePending {
+		return nil, fmt.Errorf("%w: %s", domain.ErrDriverNotOffered, driverID)
+	}
+
+	return attempt, nil
+}
+
+// offeredDriverIDs lists every driver the trip has already been offered to
+func offeredDriverIDs(trip *types.Trip) []string {
+	ids := make([]string, 0, len(trip.DispatchAttempts))
+	for _, attempt := range trip.DispatchAttempts {
+		ids = append(ids, attempt.DriverID)
+	}
+	return ids
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// The fakes embed the interfaces they stand in for, so a call the test did not expect panics
+
+// fakeTripRepository keeps trips in memory and stores them the way MongoTripRepository does:
+// Update leaves the trace alone, and AppendTrace only adds to an in-progress trip of the driver
+type fakeTripRepository struct {
+	domain.TripRepository
+	trips map[string]*types.Trip
+}
+
+func newFakeTripRepository(trips ...*types.Trip) *fakeTripRepository {
+	r := &fakeTripRepository{trips: make(map[string]*types.Trip)}
+	for _, trip := range trips {
+		r.trips[trip.ID] = copyTrip(trip)
+	}
+	return r
+}
+
+func (r *fakeTripRepository) GetByID(ctx context.Context, id string) (*types.Trip, error) {
+	trip, ok := r.trips[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyTrip(trip), nil
+}
+
+func (r *fakeTripRepository) Update(ctx context.Context, trip *types.Trip, expected types.TripStatus) error {
+	stored, ok := r.trips[trip.ID]
+	if !ok || stored.Status != expected {
+		return domain.ErrStatusConflict
+	}
+
+	updated := copyTrip(trip)
+	updated.Trace = stored.Trace
+	r.trips[trip.ID] = updated
+	return nil
+}
+
+func (r *fakeTripRepository) AppendTrace(ctx context.Context, driverID string, point *types.TracePoint) error {
+	for _, trip := range r.trips {
+		if trip.Driver != nil && trip.Driver.ID == driverID && trip.Status == types.TripStatusInProgress {
+			trip.Trace = append(trip.Trace, point)
+			return nil
+		}
+	}
+	return nil
+}
+
+// copyTrip copies a trip deeply through its BSON form, as stored in MongoDB, so the service cannot
+// change the stored one without saving it
+func copyTrip(trip *types.Trip) *types.Trip {
+	raw, err := bson.Marshal(trip)
+	if err != nil {
+		panic(err)
+	}
+	var copied types.Trip
+	if err := bson.Unmarshal(raw, &copied); err != nil {
+		panic(err)
+	}
+	return &copied
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeEventPublisher records the routing keys of the events published
+type fakeEventPublisher struct {
+	domain.EventPublisher
+	published []string
+}
+
+func (p *fakeEventPublisher) PublishTripStarted(ctx context.Context, trip *types.Trip) error {
+	p.published = append(p.published, "trip.event.started")
+	return nil
+}
+
+func (p *fakeEventPublisher) PublishTripCompleted(ctx context.Context, trip *types.Trip) error {
+	p.published = append(p.published, "trip.event.completed")
+	return nil
+}
+
+func (p *fakeEventPublisher) PublishTripCancelled(ctx context.Context, trip *types.Trip) error {
+	p.published = append(p.published, "trip.event.cancelled")
+	return nil
+}
+
+func (p *fakeEventPublisher) PublishPoolRiderLeft(ctx context.Context, trip *types.Trip) error {
+	p.published = append(p.published, "trip.event.pool_rider_left")
+	return nil
+}
+
+type fakeDriverRepository struct {
+	domain.DriverRepository
+	available map[string]bool
+}
+
+func (r *fakeDriverRepository) SetAvailable(ctx context.Context, driverID string, available bool) error {
+	if r.available == nil {
+		r.available = make(map[string]bool)
+	}
+	r.available[driverID] = available
+	return nil
+}
+
+// fakeOSRMClient routes every trip the same, with one leg per pair of consecutive stops
+type fakeOSRMClient struct{}
+
+func (fakeOSRMClient) GetRoute(ctx context.Context, pickup, destination *types.Coordinate, waypoints []*types.Coordinate) (*types.Route, error) {
+	legs := make([]*types.RouteLeg, len(waypoints)+1)
+	for i := range legs {
+		legs[i] = &types.RouteLeg{}
+	}
+	return &types.Route{Distance: 5000, Duration: 600, Legs: legs}, nil
+}
+
+// fakeFareCalculator prices every route at a flat amount
+type fakeFareCalculator struct {
+	domain.FareCalculator
+	priceInCents int64
+}
+
+func (c fakeFareCalculator) RepriceFare(ctx context.Context, fare *types.RouteFare, route *types.Route) ([]*types.FareLineItem, error) {
+	return []*types.FareLineItem{{Type: types.FareLineItemDistance, AmountInCents: c.priceInCents}}, nil
+}
+
+type testService struct {
+	*TripServiceImpl
+	repo    *fakeTripRepository
+	drivers *fakeDriverRepository
+	events  *fakeEventPublisher
+}
+
+// newTestService returns a trip service storing the given trips in memory
+func newTestService(t *testing.T, trips ...*types.Trip) *testService {
+	t.Helper()
+
+	ts := &testService{
+		repo:    newFakeTripRepository(trips...),
+		drivers: &fakeDriverRepository{},
+		events:  &fakeEventPublisher{},
+	}
+	ts.TripServiceImpl = &TripServiceImpl{
+		cfg:            DefaultConfig(),
+		tx:             fakeTransactor{},
+		repo:           ts.repo,
+		driverRepo:     ts.drivers,
+		osrmClient:     fakeOSRMClient{},
+		fareCalculator: fakeFareCalculator{priceInCents: 1000},
+		eventPublisher: ts.events,
+	}
+	return ts
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/faretoken"
+	sharedTypes "ride-sharing/shared/types"
+)
+
+// signFare issues the token a rider must present to book a quoted fare
+func (s *TripServiceImpl) signFare(fare *types.RouteFare) (string, error) {
+	return s.fareTokens.Sign(faretoken.Claims{
+		FareID:            fare.ID,
+		UserID:            fare.UserID,
+		Pickup:            sharedTypes.Coordinate{Latitude: fare.Pickup.Latitude, Longitude: fare.Pickup.Longitude},
+		Destination:       sharedTypes.Coordinate{Latitude: fare.Destination.Latitude, Longitude: fare.Destination.Longitude},
+		Waypoints:         waypointsToClaims(fare.Waypoints),
+		PackageSlug:       string(fare.PackageSlug),
+		TotalPriceInCents: fare.TotalPriceInCents,
+		PromoCode:         fare.PromoCode,
+		DiscountInCents:   fare.DiscountInCents,
+		PickupAt:          unixOrZero(fare.PickupAt),
+		ExpiresAt:         fare.ExpiresAt.Unix(),
+	})
+}
+
+// verifiedFare checks a fare token and returns the stored fare it vouches for.
+// The token proves who the fare was quoted to and what it costs; the route and the pricing it was
+// quoted with are only in the fare store, so a fare that cannot be loaded cannot be booked.
+func (s *TripServiceImpl) verifiedFare(ctx context.Context, userID, fareID, token string) (*types.RouteFare, error) {
+	claims, err := s.fareTokens.Verify(token, time.Now())
+	if errors.Is(err, faretoken.ErrExpired) {
+		return nil, domain.ErrFareExpired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrFareTokenInvalid, err)
+	}
+
+	if claims.FareID != fareID {
+		return nil, fmt.Errorf("%w: token was issued for another fare", domain.ErrFareTokenInvalid)
+	}
+
+	if claims.UserID != userID {
+		return nil, domain.ErrFareNotOwned
+	}
+
+	stored, err := s.fareRepo.GetByID(ctx, fareID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fare: %w", err)
+	}
+
+	if stored == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrFareNotFound, fareID)
+	}
+
+	if stored.TotalPriceInCents != claims.TotalPriceInCents || string(stored.PackageSlug) != claims.PackageSlug || unixOrZero(stored.PickupAt) != claims.PickupAt || stored.PromoCode != claims.PromoCode {
+		return nil, fmt.Errorf("%w: token does not match the quoted fare", domain.ErrFareTokenInvalid)
+	}
+
+	return stored, nil
+}
+
+// unixOrZero converts an optional time to a Unix timestamp, 0 when unset
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+func waypointsToClaims(waypoints []*types.Coordinate) []sharedTypes.Coordinate {
+	result := make([]sharedTypes.Coordinate, 0, len(waypoints))
+	for _, w := range waypoints {
+		result = append(result, sharedTypes.Coordinate{Latitude: w.Latitude, Longitude: w.Longitude})
+	}
+	return result
+}
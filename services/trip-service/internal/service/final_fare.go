@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/util"
+)
+
+// minTracePoints is how many recorded locations are needed to measure a trip
+const minTracePoints = 2
+
+// settleFare measures the trip from the driver's location trace, re-prices it with the pricing
+// version it was quoted with and decides what the rider is charged: the upfront price, unless
+// the metered one differs from it by more than FareAdjustmentThresholdPercent.
+// Pool trips are split between their riders instead, see settlePoolFare.
+func (s *TripServiceImpl) settleFare(ctx context.Context, trip *types.Trip) *types.FinalFare {
+	if isPool(trip) {
+		return s.settlePoolFare(ctx, trip)
+	}
+
+	fare := trip.SelectedFare
+	if fare == nil {
+		return nil
+	}
+
+	final := &types.FinalFare{
+		UpfrontPriceInCents: fare.TotalPriceInCents,
+		ChargedPriceInCents: fare.TotalPriceInCents,
+		Reason:              types.FareAdjustmentWithinThreshold,
+		CalculatedAt:        time.Now(),
+	}
+
+	if len(trip.Trace) < minTracePoints {
+		final.Reason = types.FareAdjustmentInsufficientTrace
+		return final
+	}
+
+	final.DistanceMeters = traceDistance(trip.Trace)
+	final.DurationSeconds = traceDuration(trip)
+
+	breakdown, err := s.fareCalculator.RepriceFare(ctx, fare, &types.Route{
+		Distance: final.DistanceMeters,
+		Duration: final.DurationSeconds,
+	})
+	if err != nil {
+		log.Printf("Failed to re-price trip %s, keeping the upfront price: %v", trip.ID, err)
+		final.Reason = types.FareAdjustmentPricingUnavailable
+		return final
+	}
+
+	// The discount granted when booking still applies to the metered price
+	if fare.DiscountInCents > 0 {
+		breakdown = append(breakdown, &types.FareLineItem{
+			Type:          types.FareLineItemPromoDiscount,
+			AmountInCents: -min(fare.DiscountInCents, types.SumLineItems(breakdown)),
+		})
+	}
+
+	metered := types.SumLineItems(breakdown)
+	final.MeteredPriceInCents = metered
+	final.MeteredBreakdown = breakdown
+
+	threshold := fare.TotalPriceInCents * s.cfg.FareAdjustmentThresholdPercent / 100
+	switch {
+	case metered-fare.TotalPriceInCents > threshold:
+		final.ChargedPriceInCents = metered
+		final.Reason = types.FareAdjustmentLongerTrip
+	case fare.TotalPriceInCents-metered > threshold:
+		final.ChargedPriceInCents = metered
+		final.Reason = types.FareAdjustmentShorterTrip
+	}
+
+	return final
+}
+
+// traceDistance sums the straight-line distances between consecutive trace points, in meters
+func traceDistance(trace []*types.TracePoint) float64 {
+	var distance float64
+	for i := 1; i < len(trace); i++ {
+		from, to := trace[i-1].Location, trace[i].Location
+		distance += util.HaversineDistance(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	}
+	return distance
+}
+
+// traceDuration is the time between pickup and drop-off, in seconds
+func traceDuration(trip *types.Trip) float64 {
+	start, end := trip.Trace[0].RecordedAt, trip.Trace[len(trip.Trace)-1].RecordedAt
+	if trip.StartedAt != nil {
+		start = *trip.StartedAt
+	}
+	if trip.CompletedAt != nil {
+		end = *trip.CompletedAt
+	}
+	return end.Sub(start).Seconds()
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/util"
+)
+
+// StartTrip marks the rider as picked up by the assigned driver.
+// The driver must be within PickupRadiusMeters of the pickup; when the command carries no location
+// the driver's last reported one is used.
+func (s *TripServiceImpl) StartTrip(ctx context.Context, tripID string, driverID string, location *types.Coordinate) (*types.Trip, error) {
+	trip, err := s.getTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isParticipant(trip, driverID, types.TripActorDriver) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotTripParticipant, driverID)
+	}
+
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusInProgress); err != nil {
+		return nil, err
+	}
+
+	if location == nil {
+		driver, err := s.driverRepo.GetByID(ctx, driverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get driver: %w", err)
+		}
+		if driver != nil {
+			location = driver.Location
+		}
+	}
+
+	if err := s.checkAtPickup(trip, location); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	trip.Status = types.TripStatusInProgress
+	trip.StartedAt = &now
+
+	// Metering starts at the pickup, not at the driver's next location update
+	var pickupPoint *types.TracePoint
+	if location != nil {
+		pickupPoint = &types.TracePoint{Location: location, RecordedAt: now}
+		trip.Trace = []*types.TracePoint{pickupPoint}
+	}
+	if isPool(trip) {
+		// The first stop of a pool trip is the pickup of the rider just picked up
+		reachStop(trip, 0, now)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to start trip: %w", err)
+		}
+
+		// Update leaves the trace alone, so the pickup is pushed to it once the trip is in progress
+		if pickupPoint != nil {
+			if err := s.repo.AppendTrace(ctx, driverID, pickupPoint); err != nil {
+				return fmt.Errorf("failed to record pickup location: %w", err)
+			}
+		}
+
+		if err := s.eventPublisher.PublishTripStarted(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish trip started event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+// MarkStopReached records that the assigned driver reached an intermediate stop.
+// Stops must be reached in order, so the index must be the first stop not reached yet.
+func (s *TripServiceImpl) MarkStopReached(ctx context.Context, tripID string, driverID string, stopIndex int) (*types.Trip, error) {
+	trip, err := s.getTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isParticipant(trip, driverID, types.TripActorDriver) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotTripParticipant, driverID)
+	}
+
+	if trip.Status != types.TripStatusInProgress {
+		return nil, fmt.Errorf("%w: trip is %s", domain.ErrInvalidStop, trip.Status)
+	}
+
+	if next := nextStop(trip); stopIndex != next {
+		return nil, fmt.Errorf("%w: stop %d reached while the next stop is %d", domain.ErrInvalidStop, stopIndex, next)
+	}
+
+	reachStop(trip, stopIndex, time.Now())
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, types.TripStatusInProgress); err != nil {
+			return fmt.Errorf("failed to mark stop reached: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishStopReached(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish stop reached event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+// CompleteTrip marks the trip as finished by the assigned driver, settles its final fare
+// and frees the driver for new trips
+func (s *TripServiceImpl) CompleteTrip(ctx context.Context, tripID string, driverID string) (*types.Trip, error) {
+	trip, err := s.getTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isParticipant(trip, driverID, types.TripActorDriver) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotTripParticipant, driverID)
+	}
+
+	from := trip.Status
+	if err := domain.ValidateTransition(from, types.TripStatusCompleted); err != nil {
+		return nil, err
+	}
+
+	if err := s.completeTrip(ctx, trip, from, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+// completeTrip ends a trip at the given time, settles its final fare and frees its driver.
+// The before events are published ahead of trip.event.completed, in the same transaction.
+func (s *TripServiceImpl) completeTrip(ctx context.Context, trip *types.Trip, from types.TripStatus, now time.Time, before ...func(ctx context.Context, trip *types.Trip) error) error {
+	trip.Status = types.TripStatusCompleted
+	trip.CompletedAt = &now
+	if isPool(trip) {
+		// Whoever is still on board gets off at the end of the trip
+		for i := nextStop(trip); i >= 0; i = nextStop(trip) {
+			reachStop(trip, i, now)
+		}
+	}
+	trip.FinalFare = s.settleFare(ctx, trip)
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to complete trip: %w", err)
+		}
+
+		if trip.Driver != nil {
+			if err := s.driverRepo.SetAvailable(ctx, trip.Driver.ID, true); err != nil {
+				return fmt.Errorf("failed to release driver: %w", err)
+			}
+		}
+
+		for _, publish := range before {
+			if err := publish(ctx, trip); err != nil {
+				return fmt.Errorf("failed to publish event: %w", err)
+			}
+		}
+
+		if err := s.eventPublisher.PublishTripCompleted(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish trip completed event: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkAtPickup returns ErrDriverNotAtPickup unless the location is close enough to the trip's pickup
+func (s *TripServiceImpl) checkAtPickup(trip *types.Trip, location *types.Coordinate) error {
+	if trip.Pickup == nil {
+		return nil
+	}
+
+	if location == nil {
+		return fmt.Errorf("%w: driver location unknown", domain.ErrDriverNotAtPickup)
+	}
+
+	distance := util.HaversineDistance(trip.Pickup.Latitude, trip.Pickup.Longitude, location.Latitude, location.Longitude)
+	if distance > s.cfg.PickupRadiusMeters {
+		return fmt.Errorf("%w: %.0fm away", domain.ErrDriverNotAtPickup, distance)
+	}
+
+	return nil
+}
+
+// nextStop returns the index of the first stop not reached yet, or -1 when all were reached
+func nextStop(trip *types.Trip) int {
+	for i, stop := range trip.Stops {
+		if stop.ReachedAt == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// newTripStops turns the waypoints of a fare into the stops of a trip
+func newTripStops(waypoints []*types.Coordinate) []*types.TripStop {
+	if len(waypoints) == 0 {
+		return nil
+	}
+
+	stops := make([]*types.TripStop, 0, len(waypoints))
+	for _, waypoint := range waypoints {
+		stops = append(stops, &types.TripStop{Location: waypoint})
+	}
+	return stops
+}
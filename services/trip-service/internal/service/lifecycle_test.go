@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+func TestStartTripRecordsPickupInTrace(t *testing.T) {
+	pickup := &types.Coordinate{Latitude: 52.5200, Longitude: 13.4050}
+	ts := newTestService(t, &types.Trip{
+		ID:          "trip-1",
+		UserID:      "rider-1",
+		Status:      types.TripStatusDriverAssigned,
+		Driver:      &types.Driver{ID: "driver-1"},
+		Pickup:      pickup,
+		Destination: &types.Coordinate{Latitude: 52.5300, Longitude: 13.4200},
+	})
+
+	// Within PickupRadiusMeters of the pickup
+	at := &types.Coordinate{Latitude: 52.5201, Longitude: 13.4051}
+	if _, err := ts.StartTrip(context.Background(), "trip-1", "driver-1", at); err != nil {
+		t.Fatalf("StartTrip: %v", err)
+	}
+
+	stored := ts.repo.trips["trip-1"]
+	if stored.Status != types.TripStatusInProgress {
+		t.Fatalf("status = %s, want %s", stored.Status, types.TripStatusInProgress)
+	}
+	if len(stored.Trace) != 1 {
+		t.Fatalf("stored trace has %d points, want the pickup", len(stored.Trace))
+	}
+	if got := stored.Trace[0].Location; *got != *at {
+		t.Errorf("first trace point = %+v, want the pickup location %+v", *got, *at)
+	}
+}
+
+func TestStartTripPickupIsMetered(t *testing.T) {
+	pickup := &types.Coordinate{Latitude: 52.5200, Longitude: 13.4050}
+	ts := newTestService(t, &types.Trip{
+		ID:           "trip-1",
+		UserID:       "rider-1",
+		Status:       types.TripStatusDriverAssigned,
+		Driver:       &types.Driver{ID: "driver-1"},
+		Pickup:       pickup,
+		SelectedFare: &types.RouteFare{TotalPriceInCents: 1000},
+	})
+	ctx := context.Background()
+
+	if _, err := ts.StartTrip(ctx, "trip-1", "driver-1", pickup); err != nil {
+		t.Fatalf("StartTrip: %v", err)
+	}
+
+	// A single location update after the pickup is enough to measure the trip from the pickup on
+	moved := &types.TracePoint{Location: &types.Coordinate{Latitude: 52.5300, Longitude: 13.4050}, RecordedAt: time.Now()}
+	if err := ts.repo.AppendTrace(ctx, "driver-1", moved); err != nil {
+		t.Fatal(err)
+	}
+
+	trip, err := ts.CompleteTrip(ctx, "trip-1", "driver-1")
+	if err != nil {
+		t.Fatalf("CompleteTrip: %v", err)
+	}
+	if trip.FinalFare == nil || trip.FinalFare.Reason == types.FareAdjustmentInsufficientTrace {
+		t.Fatalf("final fare = %+v, want the trip measured from the pickup", trip.FinalFare)
+	}
+	if trip.FinalFare.DistanceMeters < 1000 {
+		t.Errorf("metered %.0fm, want the ~1.1km from the pickup", trip.FinalFare.DistanceMeters)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// OfferTimeoutWorker periodically expires driver offers that were never answered and dispatches the trips
+// a failed dispatch left without an offer
+type OfferTimeoutWorker struct {
+	service  domain.TripService
+	interval time.Duration
+}
+
+// NewOfferTimeoutWorker creates a worker sweeping for lapsed offers every interval
+func NewOfferTimeoutWorker(service domain.TripService, interval time.Duration) *OfferTimeoutWorker {
+	return &OfferTimeoutWorker{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Start runs the worker in the background until the context is cancelled
+func (w *OfferTimeoutWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.service.ExpireOffers(ctx); err != nil {
+					log.Printf("Failed to expire driver offers: %v", err)
+				}
+				if err := w.service.RedispatchStalled(ctx); err != nil {
+					log.Printf("Failed to dispatch stalled trips: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Started offer timeout worker (every %v)", w.interval)
+}
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/util"
+)
+
+const (
+	// poolCandidateLimit bounds how many recent pool trips are considered for a rider
+	poolCandidateLimit = 20
+
+	// poolInsertionsTried bounds how many pickup and drop-off positions per pool trip are routed with OSRM
+	poolInsertionsTried = 2
+)
+
+// poolJoinableStatuses are the statuses in which a pool trip can take another rider
+var poolJoinableStatuses = []types.TripStatus{
+	types.TripStatusCreated,
+	types.TripStatusDriverFound,
+	types.TripStatusDriverAssigned,
+	types.TripStatusInProgress,
+}
+
+// newPool plans a new pool trip for its first rider: their pickup, then their drop-off
+func newPool(userID string, fare *types.RouteFare) ([]*types.TripStop, []*types.PoolRider) {
+	stops := []*types.TripStop{
+		{Location: fare.Pickup, Kind: types.TripStopPickup, UserID: userID},
+		{Location: fare.Destination, Kind: types.TripStopDropoff, UserID: userID},
+	}
+	return stops, []*types.PoolRider{newPoolRider(userID, fare)}
+}
+
+func newPoolRider(userID string, fare *types.RouteFare) *types.PoolRider {
+	rider := &types.PoolRider{
+		UserID:      userID,
+		FareID:      fare.ID,
+		FareInCents: fare.TotalPriceInCents,
+		JoinedAt:    time.Now(),
+	}
+	if fare.Route != nil {
+		rider.DirectDistance = fare.Route.Distance
+		rider.DirectDuration = fare.Route.Duration
+	}
+	return rider
+}
+
+// joinPool adds the rider to the most recent pool trip whose route can absorb their pickup and drop-off
+// within the detour budget. It returns a nil trip when none can, so the rider starts a new one.
+func (s *TripServiceImpl) joinPool(ctx context.Context, userID string, fare *types.RouteFare, promoCode string) (*types.Trip, error) {
+	// Without the rider's own route their detour cannot be bounded
+	if fare.Route == nil || fare.Pickup == nil || fare.Destination == nil {
+		return nil, nil
+	}
+
+	trips, err := s.repo.FindPoolTrips(ctx, poolJoinableStatuses, poolCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pool trips: %w", err)
+	}
+
+	for _, trip := range trips {
+		if !s.hasRoomFor(trip, userID, fare.Pickup) {
+			continue
+		}
+
+		stops, route, ok := s.matchPool(ctx, trip, userID, fare)
+		if !ok {
+			continue
+		}
+
+		joined, err := s.addPoolRider(ctx, trip, userID, fare, promoCode, stops, route)
+		if errors.Is(err, domain.ErrStatusConflict) {
+			// The trip changed while it was matched, try the next one
+			continue
+		}
+		return joined, err
+	}
+
+	return nil, nil
+}
+
+// hasRoomFor reports whether a pool trip has a free seat and an upcoming stop near the pickup
+func (s *TripServiceImpl) hasRoomFor(trip *types.Trip, userID string, pickup *types.Coordinate) bool {
+	// A rider who left a trip does not get back on it
+	if joinedPool(trip, userID) || len(activePoolRiders(trip)) >= s.cfg.PoolCapacity {
+		return false
+	}
+
+	from := firstInsertion(trip)
+	if from < 0 {
+		return false
+	}
+
+	for _, stop := range trip.Stops[from-1:] {
+		distance := util.HaversineDistance(pickup.Latitude, pickup.Longitude, stop.Location.Latitude, stop.Location.Longitude)
+		if distance <= s.cfg.PoolSearchRadiusMeters {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPool finds where the rider's pickup and drop-off fit into the upcoming stops of a pool trip.
+// Insertions are ranked by their straight-line length and the shortest few are routed with OSRM,
+// until one keeps every rider within the detour budget.
+func (s *TripServiceImpl) matchPool(ctx context.Context, trip *types.Trip, userID string, fare *types.RouteFare) ([]*types.TripStop, *types.Route, bool) {
+	pickup := &types.TripStop{Location: fare.Pickup, Kind: types.TripStopPickup, UserID: userID}
+	dropoff := &types.TripStop{Location: fare.Destination, Kind: types.TripStopDropoff, UserID: userID}
+	riders := append(activePoolRiders(trip), newPoolRider(userID, fare))
+
+	plans := poolInsertions(trip.Stops, firstInsertion(trip), pickup, dropoff)
+	for i, stops := range plans {
+		if i == poolInsertionsTried {
+			break
+		}
+
+		route, err := s.routeStops(ctx, stops)
+		if err != nil {
+			log.Printf("Failed to route pool trip %s for rider %s: %v", trip.ID, userID, err)
+			return nil, nil, false
+		}
+
+		if s.withinDetour(riders, stops, route) {
+			return stops, route, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// addPoolRider redeems the rider's promo code and stores them on the pool trip with its new stops and route
+func (s *TripServiceImpl) addPoolRider(ctx context.Context, trip *types.Trip, userID string, fare *types.RouteFare, promoCode string, stops []*types.TripStop, route *types.Route) (*types.Trip, error) {
+	from := trip.Status
+	setPoolStops(trip, stops)
+	trip.Route = route
+	trip.Riders = append(trip.Riders, newPoolRider(userID, fare))
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.redeemPromo(ctx, userID, fare, promoCode, poolRedemptionID(trip.ID, userID)); err != nil {
+			return err
+		}
+
+		if err := s.repo.AddPoolRider(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to join pool trip: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishPoolRiderJoined(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish pool rider joined event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+// leavePool removes a rider from a pool trip other riders share or were dropped off from.
+// Their upcoming stops are dropped and the rest of the trip is routed again. When nobody is left on board
+// the trip is completed, so the riders already dropped off are charged their share. The trip itself is
+// not cancelled, so it returns the rider's own cancellation.
+func (s *TripServiceImpl) leavePool(ctx context.Context, trip *types.Trip, rider *types.PoolRider, reason string) (*types.Trip, *types.Cancellation, error) {
+	from := trip.Status
+	now := time.Now()
+
+	rider.Cancellation = &types.Cancellation{
+		CancelledBy: types.TripActorRider,
+		ActorID:     rider.UserID,
+		Reason:      reason,
+		FeeInCents:  s.cancellationFee(trip, types.TripActorRider, now),
+		CancelledAt: now,
+	}
+
+	setPoolStops(trip, slices.DeleteFunc(trip.Stops, func(stop *types.TripStop) bool {
+		return stop.UserID == rider.UserID && stop.ReachedAt == nil
+	}))
+
+	// The trip belongs to a rider still on it, who its events are addressed to
+	if trip.UserID == rider.UserID {
+		trip.UserID = remainingPoolRiders(trip)[0].UserID
+	}
+
+	if len(activePoolRiders(trip)) == 0 {
+		if err := domain.ValidateTransition(from, types.TripStatusCompleted); err != nil {
+			return nil, nil, err
+		}
+		if err := s.completeTrip(ctx, trip, from, now, s.eventPublisher.PublishPoolRiderLeft); err != nil {
+			return nil, nil, err
+		}
+		return trip, rider.Cancellation, nil
+	}
+
+	route, err := s.routeStops(ctx, trip.Stops)
+	if err != nil {
+		log.Printf("Failed to re-route pool trip %s, keeping its route: %v", trip.ID, err)
+	} else {
+		trip.Route = route
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, trip, from); err != nil {
+			return fmt.Errorf("failed to leave pool trip: %w", err)
+		}
+
+		if err := s.eventPublisher.PublishPoolRiderLeft(ctx, trip); err != nil {
+			return fmt.Errorf("failed to publish pool rider left event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return trip, rider.Cancellation, nil
+}
+
+// settlePoolFare meters the whole pool trip like a private one and splits the metered price between
+// the riders who stayed, in proportion to the length of their own routes. Nobody pays more than their
+// upfront quote; when the trip cannot be metered every rider pays exactly that.
+func (s *TripServiceImpl) settlePoolFare(ctx context.Context, trip *types.Trip) *types.FinalFare {
+	riders := remainingPoolRiders(trip)
+
+	final := &types.FinalFare{
+		Reason:       types.FareAdjustmentPoolSplit,
+		CalculatedAt: time.Now(),
+	}
+	for _, rider := range riders {
+		final.UpfrontPriceInCents += rider.FareInCents
+		rider.ChargedPriceInCents = rider.FareInCents
+	}
+	final.ChargedPriceInCents = final.UpfrontPriceInCents
+
+	if trip.SelectedFare == nil || len(trip.Trace) < minTracePoints {
+		final.Reason = types.FareAdjustmentInsufficientTrace
+		return final
+	}
+
+	final.DistanceMeters = traceDistance(trip.Trace)
+	final.DurationSeconds = traceDuration(trip)
+
+	breakdown, err := s.fareCalculator.RepriceFare(ctx, trip.SelectedFare, &types.Route{
+		Distance: final.DistanceMeters,
+		Duration: final.DurationSeconds,
+	})
+	if err != nil {
+		log.Printf("Failed to re-price pool trip %s, keeping the upfront prices: %v", trip.ID, err)
+		final.Reason = types.FareAdjustmentPricingUnavailable
+		return final
+	}
+
+	metered := types.SumLineItems(breakdown)
+	final.MeteredPriceInCents = metered
+	final.MeteredBreakdown = breakdown
+
+	weights := make([]float64, len(riders))
+	for i, rider := range riders {
+		weights[i] = rider.DirectDistance
+	}
+
+	final.ChargedPriceInCents = 0
+	for i, share := range splitByWeight(metered, weights) {
+		riders[i].ChargedPriceInCents = min(share, riders[i].FareInCents)
+		final.ChargedPriceInCents += riders[i].ChargedPriceInCents
+	}
+
+	return final
+}
+
+// withinDetour reports whether no rider would ride longer than their own route plus PoolMaxDetour.
+// Ride times are added up from the route legs between a rider's pickup and drop-off.
+func (s *TripServiceImpl) withinDetour(riders []*types.PoolRider, stops []*types.TripStop, route *types.Route) bool {
+	if len(route.Legs) != len(stops)-1 {
+		return false
+	}
+
+	budget := s.cfg.PoolMaxDetour.Seconds()
+	for _, rider := range riders {
+		// Riders booked without their own route have nothing to compare with
+		if rider.DirectDuration == 0 || rider.DroppedOffAt != nil {
+			continue
+		}
+
+		pickup, dropoff := riderStops(stops, rider.UserID)
+		if pickup < 0 || dropoff < pickup {
+			return false
+		}
+
+		var ride float64
+		for _, leg := range route.Legs[pickup:dropoff] {
+			ride += leg.Duration
+		}
+		if ride-rider.DirectDuration > budget {
+			return false
+		}
+	}
+	return true
+}
+
+// routeStops asks OSRM for the route through the stops of a pool trip, in order
+func (s *TripServiceImpl) routeStops(ctx context.Context, stops []*types.TripStop) (*types.Route, error) {
+	if len(stops) < 2 {
+		return nil, fmt.Errorf("a route needs at least 2 stops, got %d", len(stops))
+	}
+
+	waypoints := make([]*types.Coordinate, 0, len(stops)-2)
+	for _, stop := range stops[1 : len(stops)-1] {
+		waypoints = append(waypoints, stop.Location)
+	}
+
+	return s.osrmClient.GetRoute(ctx, stops[0].Location, stops[len(stops)-1].Location, waypoints)
+}
+
+// poolInsertions returns every way to insert a pickup and its drop-off into the stops at or after
+// index from, keeping the order of the existing stops, shortest straight-line plan first
+func poolInsertions(stops []*types.TripStop, from int, pickup, dropoff *types.TripStop) [][]*types.TripStop {
+	if from < 0 {
+		return nil
+	}
+
+	var plans [][]*types.TripStop
+	for i := from; i <= len(stops); i++ {
+		for j := i; j <= len(stops); j++ {
+			plan := make([]*types.TripStop, 0, len(stops)+2)
+			plan = append(plan, stops[:i]...)
+			plan = append(plan, pickup)
+			plan = append(plan, stops[i:j]...)
+			plan = append(plan, dropoff)
+			plan = append(plan, stops[j:]...)
+			plans = append(plans, plan)
+		}
+	}
+
+	sort.SliceStable(plans, func(a, b int) bool {
+		return planLength(plans[a]) < planLength(plans[b])
+	})
+	return plans
+}
+
+// firstInsertion returns the first position a new stop can be inserted at, or -1 when the trip has
+// no upcoming stop left. The first stop always stays first, so the driver is not redirected before it.
+func firstInsertion(trip *types.Trip) int {
+	next := nextStop(trip)
+	if next < 0 {
+		return -1
+	}
+	return max(next, 1)
+}
+
+// planLength is the straight-line length of the path through the stops, in meters
+func planLength(stops []*types.TripStop) float64 {
+	var length float64
+	for i := 1; i < len(stops); i++ {
+		from, to := stops[i-1].Location, stops[i].Location
+		length += util.HaversineDistance(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	}
+	return length
+}
+
+// splitByWeight splits an amount in proportion to the weights, evenly when they are all zero
+func splitByWeight(amount int64, weights []float64) []int64 {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	shares := make([]int64, len(weights))
+	for i, weight := range weights {
+		if total == 0 {
+			weight, total = 1, float64(len(weights))
+		}
+		shares[i] = int64(math.Round(float64(amount) * weight / total))
+	}
+	return shares
+}
+
+// setPoolStops replaces the stops of a pool trip, keeping its pickup and destination in line with them
+func setPoolStops(trip *types.Trip, stops []*types.TripStop) {
+	trip.Stops = stops
+	if len(stops) > 0 {
+		trip.Pickup = stops[0].Location
+		trip.Destination = stops[len(stops)-1].Location
+	}
+}
+
+// riderStops returns the indexes of a rider's pickup and drop-off stops, -1 when missing
+func riderStops(stops []*types.TripStop, userID string) (int, int) {
+	pickup, dropoff := -1, -1
+	for i, stop := range stops {
+		if stop.UserID != userID {
+			continue
+		}
+		switch stop.Kind {
+		case types.TripStopPickup:
+			pickup = i
+		case types.TripStopDropoff:
+			dropoff = i
+		}
+	}
+	return pickup, dropoff
+}
+
+// reachStop marks a stop as reached and, on pool trips, the rider getting on or off there
+func reachStop(trip *types.Trip, index int, at time.Time) {
+	stop := trip.Stops[index]
+	stop.ReachedAt = &at
+
+	rider := poolRider(trip, stop.UserID)
+	if rider == nil {
+		return
+	}
+
+	switch stop.Kind {
+	case types.TripStopPickup:
+		rider.PickedUpAt = &at
+	case types.TripStopDropoff:
+		rider.DroppedOffAt = &at
+	}
+}
+
+// poolRider returns the rider of a pool trip who has not left it, or nil
+func poolRider(trip *types.Trip, userID string) *types.PoolRider {
+	if userID == "" {
+		return nil
+	}
+	for _, rider := range trip.Riders {
+		if rider.UserID == userID && rider.Cancellation == nil {
+			return rider
+		}
+	}
+	return nil
+}
+
+// remainingPoolRiders returns the riders of a pool trip who have not left it, including those dropped off
+func remainingPoolRiders(trip *types.Trip) []*types.PoolRider {
+	var riders []*types.PoolRider
+	for _, rider := range trip.Riders {
+		if rider.Cancellation == nil {
+			riders = append(riders, rider)
+		}
+	}
+	return riders
+}
+
+// joinedPool reports whether the user ever joined a pool trip, including riders who left it
+func joinedPool(trip *types.Trip, userID string) bool {
+	for _, rider := range trip.Riders {
+		if rider.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// activePoolRiders returns the riders of a pool trip who have neither left nor been dropped off
+func activePoolRiders(trip *types.Trip) []*types.PoolRider {
+	var riders []*types.PoolRider
+	for _, rider := range trip.Riders {
+		if rider.Cancellation == nil && rider.DroppedOffAt == nil {
+			riders = append(riders, rider)
+		}
+	}
+	return riders
+}
+
+// poolRedemptionID identifies the promo redemption of a rider who joined a pool trip
+func poolRedemptionID(tripID, userID string) string {
+	return tripID + ":" + userID
+}
+
+// isPool reports whether a trip is shared by pool riders
+func isPool(trip *types.Trip) bool {
+	return len(trip.Riders) > 0
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// inProgressPool returns a pool trip rider-1 is still on, after rider-2 was dropped off
+func inProgressPool() *types.Trip {
+	startedAt := time.Now().Add(-20 * time.Minute)
+	droppedOffAt := time.Now().Add(-5 * time.Minute)
+	return &types.Trip{
+		ID:        "trip-1",
+		UserID:    "rider-1",
+		Status:    types.TripStatusInProgress,
+		Driver:    &types.Driver{ID: "driver-1"},
+		StartedAt: &startedAt,
+		Stops: []*types.TripStop{
+			{Location: &types.Coordinate{Latitude: 52.50, Longitude: 13.40}, Kind: types.TripStopPickup, UserID: "rider-1", ReachedAt: &startedAt},
+			{Location: &types.Coordinate{Latitude: 52.51, Longitude: 13.41}, Kind: types.TripStopPickup, UserID: "rider-2", ReachedAt: &startedAt},
+			{Location: &types.Coordinate{Latitude: 52.52, Longitude: 13.42}, Kind: types.TripStopDropoff, UserID: "rider-2", ReachedAt: &droppedOffAt},
+			{Location: &types.Coordinate{Latitude: 52.53, Longitude: 13.43}, Kind: types.TripStopDropoff, UserID: "rider-1"},
+		},
+		Riders: []*types.PoolRider{
+			{UserID: "rider-1", FareID: "fare-1", FareInCents: 900, PickedUpAt: &startedAt},
+			{UserID: "rider-2", FareID: "fare-2", FareInCents: 700, PickedUpAt: &startedAt, DroppedOffAt: &droppedOffAt},
+		},
+	}
+}
+
+func TestLastPoolRiderLeavingCompletesTrip(t *testing.T) {
+	ts := newTestService(t, inProgressPool())
+
+	trip, cancellation, err := ts.CancelTrip(context.Background(), "trip-1", "rider-1", types.TripActorRider, "plans changed")
+	if err != nil {
+		t.Fatalf("CancelTrip: %v", err)
+	}
+
+	if cancellation == nil || cancellation.ActorID != "rider-1" {
+		t.Fatalf("cancellation = %+v, want the one of the rider who left", cancellation)
+	}
+
+	stored := ts.repo.trips["trip-1"]
+	if stored.Status != types.TripStatusCompleted {
+		t.Fatalf("status = %s, want the trip completed for the rider dropped off", stored.Status)
+	}
+	if stored.Cancellation != nil {
+		t.Errorf("trip cancelled by %s, want only the rider to leave", stored.Cancellation.ActorID)
+	}
+	if stored.FinalFare == nil {
+		t.Fatal("no final fare settled")
+	}
+	if got := stored.Riders[1].ChargedPriceInCents; got != 700 {
+		t.Errorf("dropped off rider charged %d, want their upfront 700", got)
+	}
+	if got := stored.Riders[0].ChargedPriceInCents; got != 0 {
+		t.Errorf("rider who left charged %d on top of their cancellation", got)
+	}
+	if stored.UserID != "rider-2" {
+		t.Errorf("trip belongs to %s, want the rider still on it", stored.UserID)
+	}
+	if !ts.drivers.available["driver-1"] {
+		t.Error("driver not released")
+	}
+
+	want := []string{"trip.event.pool_rider_left", "trip.event.completed"}
+	if !slices.Equal(ts.events.published, want) {
+		t.Errorf("published %v, want %v", ts.events.published, want)
+	}
+	if trip.Status != types.TripStatusCompleted {
+		t.Errorf("returned trip is %s, want completed", trip.Status)
+	}
+}
+
+func TestPoolRiderWhoLeftCannotRejoin(t *testing.T) {
+	assignedAt := time.Now()
+	ts := newTestService(t, &types.Trip{
+		ID:               "trip-1",
+		UserID:           "rider-1",
+		Status:           types.TripStatusDriverAssigned,
+		Driver:           &types.Driver{ID: "driver-1"},
+		DriverAssignedAt: &assignedAt,
+		Stops: []*types.TripStop{
+			{Location: &types.Coordinate{Latitude: 52.50, Longitude: 13.40}, Kind: types.TripStopPickup, UserID: "rider-1"},
+			{Location: &types.Coordinate{Latitude: 52.51, Longitude: 13.41}, Kind: types.TripStopPickup, UserID: "rider-2"},
+			{Location: &types.Coordinate{Latitude: 52.52, Longitude: 13.42}, Kind: types.TripStopDropoff, UserID: "rider-2"},
+			{Location: &types.Coordinate{Latitude: 52.53, Longitude: 13.43}, Kind: types.TripStopDropoff, UserID: "rider-1"},
+		},
+		Riders: []*types.PoolRider{
+			{UserID: "rider-1", FareID: "fare-1", FareInCents: 900},
+			{UserID: "rider-2", FareID: "fare-2", FareInCents: 700},
+		},
+	})
+
+	if _, _, err := ts.CancelTrip(context.Background(), "trip-1", "rider-1", types.TripActorRider, "plans changed"); err != nil {
+		t.Fatalf("CancelTrip: %v", err)
+	}
+
+	stored := ts.repo.trips["trip-1"]
+	if stored.UserID != "rider-2" {
+		t.Errorf("trip belongs to %s, want the rider still on it", stored.UserID)
+	}
+
+	pickup := &types.Coordinate{Latitude: 52.50, Longitude: 13.40}
+	if ts.hasRoomFor(stored, "rider-1", pickup) {
+		t.Error("rider who left the trip may join it again")
+	}
+	if !ts.hasRoomFor(stored, "rider-3", pickup) {
+		t.Error("a new rider may not join the trip")
+	}
+}
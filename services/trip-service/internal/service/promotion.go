@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// promotionFor loads a promo code and checks that the user may use it right now.
+// Package restrictions are checked per fare by applyPromotion.
+func (s *TripServiceImpl) promotionFor(ctx context.Context, code, userID string) (*types.Promotion, error) {
+	code = normalizePromoCode(code)
+
+	promo, err := s.promoRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	if promo == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPromoNotFound, code)
+	}
+
+	now := time.Now()
+	if !promo.ValidFrom.IsZero() && now.Before(promo.ValidFrom) {
+		return nil, fmt.Errorf("%w: %s is not valid yet", domain.ErrPromoNotApplicable, code)
+	}
+	if !promo.ValidUntil.IsZero() && now.After(promo.ValidUntil) {
+		return nil, fmt.Errorf("%w: %s has expired", domain.ErrPromoNotApplicable, code)
+	}
+
+	if promo.GlobalLimit > 0 && promo.Redemptions >= promo.GlobalLimit {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPromoLimitReached, code)
+	}
+
+	if promo.PerUserLimit > 0 {
+		used, err := s.promoRepo.CountUserRedemptions(ctx, code, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count promo redemptions: %w", err)
+		}
+		if used >= promo.PerUserLimit {
+			return nil, fmt.Errorf("%w: %s", domain.ErrPromoLimitReached, code)
+		}
+	}
+
+	if promo.FirstRideOnly {
+		hasTrips, err := s.repo.HasTrips(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check trip history: %w", err)
+		}
+		if hasTrips {
+			return nil, fmt.Errorf("%w: %s is only valid on the first ride", domain.ErrPromoNotApplicable, code)
+		}
+	}
+
+	return promo, nil
+}
+
+// redeemPromo checks the promo code of the booked fare and redeems it under the given ID.
+// Call it in the transaction that stores the booking. It does nothing when no code is given.
+func (s *TripServiceImpl) redeemPromo(ctx context.Context, userID string, fare *types.RouteFare, promoCode, redemptionID string) error {
+	if promoCode == "" {
+		return nil
+	}
+
+	promo, err := s.promotionFor(ctx, promoCode, userID)
+	if err != nil {
+		return err
+	}
+
+	// The rider booked the quoted price; a promotion changed since must be quoted again
+	quoted := fare.TotalPriceInCents
+	applyPromotion(fare, promo)
+	if fare.PromoCode == "" {
+		return fmt.Errorf("%w: %s is not valid for %s", domain.ErrPromoNotApplicable, promo.Code, fare.PackageSlug)
+	}
+	if fare.TotalPriceInCents != quoted {
+		return fmt.Errorf("%w: %s has changed since the fare was quoted", domain.ErrPromoNotApplicable, promo.Code)
+	}
+
+	redemption := &types.PromoRedemption{
+		TripID:          redemptionID,
+		Code:            promo.Code,
+		UserID:          userID,
+		DiscountInCents: fare.DiscountInCents,
+	}
+	if err := s.promoRepo.Redeem(ctx, promo, redemption); err != nil {
+		return fmt.Errorf("failed to redeem promo code: %w", err)
+	}
+
+	return nil
+}
+
+// applyPromotion replaces any discount on the fare with the one of the promotion.
+// Fares of packages the promotion does not cover are left undiscounted.
+func applyPromotion(fare *types.RouteFare, promo *types.Promotion) {
+	breakdown := make([]*types.FareLineItem, 0, len(fare.Breakdown)+1)
+	for _, item := range fare.Breakdown {
+		if item.Type != types.FareLineItemPromoDiscount {
+			breakdown = append(breakdown, item)
+		}
+	}
+	fare.TotalPriceInCents += fare.DiscountInCents
+	fare.PromoCode = ""
+	fare.DiscountInCents = 0
+
+	if len(promo.PackageSlugs) == 0 || slices.Contains(promo.PackageSlugs, fare.PackageSlug) {
+		if discount := promoDiscount(promo, fare.TotalPriceInCents); discount > 0 {
+			breakdown = append(breakdown, &types.FareLineItem{
+				Type:          types.FareLineItemPromoDiscount,
+				AmountInCents: -discount,
+			})
+			fare.TotalPriceInCents -= discount
+			fare.PromoCode = promo.Code
+			fare.DiscountInCents = discount
+		}
+	}
+
+	fare.Breakdown = breakdown
+}
+
+// promoDiscount returns the discount on a total, never more than the total itself
+func promoDiscount(promo *types.Promotion, totalInCents int64) int64 {
+	var discount int64
+	switch promo.DiscountType {
+	case types.DiscountTypePercentage:
+		discount = (totalInCents*promo.PercentOff + 50) / 100
+		if promo.MaxDiscountCents > 0 {
+			discount = min(discount, promo.MaxDiscountCents)
+		}
+	case types.DiscountTypeFixed:
+		discount = promo.AmountOffCents
+	}
+	return min(max(discount, 0), totalInCents)
+}
+
+// normalizePromoCode makes codes case-insensitive; codes are stored upper case
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
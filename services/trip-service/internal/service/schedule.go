@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// scheduledBatchSize bounds how many scheduled trips are handled per sweep
+const scheduledBatchSize = 100
+
+// validatePickupTime checks that a scheduled pickup is within the allowed booking window
+func (s *TripServiceImpl) validatePickupTime(pickupAt time.Time) error {
+	ahead := time.Until(pickupAt)
+	if ahead < s.cfg.MinScheduleAhead {
+		return fmt.Errorf("%w: must be at least %v ahead", domain.ErrInvalidPickupTime, s.cfg.MinScheduleAhead)
+	}
+	if ahead > s.cfg.MaxScheduleAhead {
+		return fmt.Errorf("%w: must be at most %v ahead", domain.ErrInvalidPickupTime, s.cfg.MaxScheduleAhead)
+	}
+	return nil
+}
+
+// newTripSchedule works out when to remind the rider and when to start looking for a driver.
+// No reminder is planned when the ride is booked after the reminder time.
+func (s *TripServiceImpl) newTripSchedule(pickupAt time.Time) *types.TripSchedule {
+	schedule := &types.TripSchedule{
+		PickupAt:   pickupAt,
+		DispatchAt: pickupAt.Add(-s.cfg.ScheduleLeadTime),
+	}
+
+	if reminderAt := pickupAt.Add(-s.cfg.ScheduleReminderLeadTime); reminderAt.After(time.Now()) {
+		schedule.ReminderAt = &reminderAt
+	}
+
+	return schedule
+}
+
+// ProcessScheduledTrips sends due reminders and starts dispatching scheduled trips whose dispatch time has come.
+// All state lives on the trips, so nothing is lost across restarts and replicas never handle a trip twice.
+func (s *TripServiceImpl) ProcessScheduledTrips(ctx context.Context) error {
+	now := time.Now()
+
+	reminders, err := s.repo.FindDueReminders(ctx, now, scheduledBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find due reminders: %w", err)
+	}
+
+	for _, trip := range reminders {
+		if err := s.sendReminder(ctx, trip, now); err != nil {
+			log.Printf("Failed to remind rider of trip %s: %v", trip.ID, err)
+		}
+	}
+
+	due, err := s.repo.FindDueScheduled(ctx, now, scheduledBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find due scheduled trips: %w", err)
+	}
+
+	for _, trip := range due {
+		if err := s.dispatchScheduled(ctx, trip); err != nil {
+			log.Printf("Failed to dispatch scheduled trip %s: %v", trip.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// sendReminder claims the reminder of a trip and notifies the rider
+func (s *TripServiceImpl) sendReminder(ctx context.Context, trip *types.Trip, now time.Time) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		claimed, err := s.repo.MarkReminderSent(ctx, trip.ID, now)
+		if err != nil || !claimed {
+			return err
+		}
+
+		trip.Schedule.ReminderSentAt = &now
+		return s.eventPublisher.PublishScheduledReminder(ctx, trip)
+	})
+}
+
+// dispatchScheduled turns a scheduled trip into an on-demand one and offers it to the closest driver
+func (s *TripServiceImpl) dispatchScheduled(ctx context.Context, trip *types.Trip) error {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateStatus(ctx, trip.ID, types.TripStatusScheduled, types.TripStatusCreated); err != nil {
+			return err
+		}
+		trip.Status = types.TripStatusCreated
+
+		return s.eventPublisher.PublishTripCreated(ctx, trip)
+	})
+	if errors.Is(err, domain.ErrStatusConflict) {
+		// Cancelled meanwhile, or another replica got to it first
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The trip is created either way; a failed dispatch is picked up by RedispatchStalled
+	if err := s.dispatchNext(ctx, trip); err != nil {
+		log.Printf("Failed to dispatch scheduled trip %s, leaving it to the sweep: %v", trip.ID, err)
+	}
+	return nil
+}
+
+// ScheduledTripWorker periodically dispatches scheduled trips and sends their reminders
+type ScheduledTripWorker struct {
+	service  domain.TripService
+	interval time.Duration
+}
+
+// NewScheduledTripWorker creates a worker processing scheduled trips every interval
+func NewScheduledTripWorker(service domain.TripService, interval time.Duration) *ScheduledTripWorker {
+	return &ScheduledTripWorker{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Start runs the worker in the background until the context is cancelled
+func (w *ScheduledTripWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.service.ProcessScheduledTrips(ctx); err != nil {
+					log.Printf("Failed to process scheduled trips: %v", err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Started scheduled trip worker (every %v)", w.interval)
+}
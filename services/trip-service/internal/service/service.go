@@ -3,97 +3,281 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/faretoken"
 )
 
+// Config holds the tunable parameters of the trip service
+type Config struct {
+	// MaxDispatchAttempts is how many drivers a trip is offered to before giving up
+	MaxDispatchAttempts int
+
+	// OfferTimeout is how long a driver has to answer a trip offer
+	OfferTimeout time.Duration
+
+	// RedispatchAfter is how long a trip may wait for a driver offer before it is dispatched again,
+	// e.g. because dispatching failed after the trip was saved
+	RedispatchAfter time.Duration
+
+	// CancellationFeeInCents is charged to riders cancelling after the free period
+	CancellationFeeInCents int64
+
+	// CancellationFreePeriod is how long after driver assignment a rider may cancel for free
+	CancellationFreePeriod time.Duration
+
+	// PickupRadiusMeters is how close to the pickup a driver must be to start the trip
+	PickupRadiusMeters float64
+
+	// FareAdjustmentThresholdPercent is how far the metered price may differ from the upfront one
+	// before the rider is charged the metered price
+	FareAdjustmentThresholdPercent int64
+
+	// ScheduleLeadTime is how long before a scheduled pickup the search for a driver starts
+	ScheduleLeadTime time.Duration
+
+	// ScheduleReminderLeadTime is how long before a scheduled pickup the rider is reminded
+	ScheduleReminderLeadTime time.Duration
+
+	// MinScheduleAhead and MaxScheduleAhead bound how far ahead a ride can be booked
+	MinScheduleAhead time.Duration
+	MaxScheduleAhead time.Duration
+
+	// ScheduledQuoteValidity is how long quotes for a scheduled ride can be booked
+	ScheduledQuoteValidity time.Duration
+
+	// PoolCapacity is how many riders can share a pool trip at once
+	PoolCapacity int
+
+	// PoolMaxDetour is how much longer than their own route a pool rider's ride may take
+	PoolMaxDetour time.Duration
+
+	// PoolSearchRadiusMeters is how close to a pool trip's upcoming stops a pickup must be to join it
+	PoolSearchRadiusMeters float64
+}
+
+// DefaultConfig returns a Config with sensible default values
+func DefaultConfig() Config {
+	return Config{
+		MaxDispatchAttempts:            5,
+		OfferTimeout:                   15 * time.Second,
+		RedispatchAfter:                30 * time.Second,
+		CancellationFeeInCents:         500,
+		CancellationFreePeriod:         2 * time.Minute,
+		PickupRadiusMeters:             200,
+		FareAdjustmentThresholdPercent: 20,
+		ScheduleLeadTime:               15 * time.Minute,
+		ScheduleReminderLeadTime:       time.Hour,
+		MinScheduleAhead:               30 * time.Minute,
+		MaxScheduleAhead:               7 * 24 * time.Hour,
+		ScheduledQuoteValidity:         30 * time.Minute,
+		PoolCapacity:                   3,
+		PoolMaxDetour:                  8 * time.Minute,
+		PoolSearchRadiusMeters:         3000,
+	}
+}
+
 // TripServiceImpl implements the TripService interface
 type TripServiceImpl struct {
-	repo          domain.TripRepository
-	osrmClient    domain.OSRMClient
+	cfg            Config
+	tx             domain.Transactor
+	repo           domain.TripRepository
+	fareRepo       domain.RideFareRepository
+	driverRepo     domain.DriverRepository
+	promoRepo      domain.PromotionRepository
+	osrmClient     domain.OSRMClient
 	fareCalculator domain.FareCalculator
+	surge          domain.SurgePricer
 	eventPublisher domain.EventPublisher
+	fareTokens     *faretoken.Signer
 }
 
 // NewTripService creates a new trip service
 func NewTripService(
+	cfg Config,
+	tx domain.Transactor,
 	repo domain.TripRepository,
+	fareRepo domain.RideFareRepository,
+	driverRepo domain.DriverRepository,
+	promoRepo domain.PromotionRepository,
 	osrmClient domain.OSRMClient,
 	fareCalculator domain.FareCalculator,
+	surge domain.SurgePricer,
 	eventPublisher domain.EventPublisher,
+	fareTokens *faretoken.Signer,
 ) domain.TripService {
 	return &TripServiceImpl{
+		cfg:            cfg,
+		tx:             tx,
 		repo:           repo,
+		fareRepo:       fareRepo,
+		driverRepo:     driverRepo,
+		promoRepo:      promoRepo,
 		osrmClient:     osrmClient,
 		fareCalculator: fareCalculator,
+		surge:          surge,
 		eventPublisher: eventPublisher,
+		fareTokens:     fareTokens,
 	}
 }
 
 // PreviewTrip calculates route and fare options without creating a trip
-func (s *TripServiceImpl) PreviewTrip(ctx context.Context, userID string, pickup, destination *types.Coordinate) (*types.Route, []*types.RouteFare, error) {
+func (s *TripServiceImpl) PreviewTrip(ctx context.Context, userID string, pickup, destination *types.Coordinate, waypoints []*types.Coordinate, pickupAt *time.Time, promoCode string) (*types.Route, []*types.RouteFare, error) {
+	if pickupAt != nil {
+		if err := s.validatePickupTime(*pickupAt); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Reject a bad promo code before doing any routing
+	var promo *types.Promotion
+	if promoCode != "" {
+		var err error
+		if promo, err = s.promotionFor(ctx, promoCode, userID); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Get route from OSRM
-	route, err := s.osrmClient.GetRoute(ctx, pickup, destination)
+	route, err := s.osrmClient.GetRoute(ctx, pickup, destination, waypoints)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get route: %w", err)
 	}
 
+	// Count the rider towards the demand of the pickup cell before pricing; rides booked ahead
+	// are not demand for now
+	if pickupAt == nil {
+		if err := s.surge.RecordDemand(ctx, userID, pickup); err != nil {
+			log.Printf("Failed to record demand for %s: %v", userID, err)
+		}
+	}
+
 	// Calculate fares
-	fares, err := s.fareCalculator.CalculateFares(ctx, route)
+	fares, err := s.fareCalculator.CalculateFares(ctx, route, pickup)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to calculate fares: %w", err)
 	}
 
+	// Pool rides go straight from pickup to destination and cannot be booked ahead
+	if len(waypoints) > 0 || pickupAt != nil {
+		fares = slices.DeleteFunc(fares, func(fare *types.RouteFare) bool {
+			return fare.PackageSlug == types.CarPackagePool
+		})
+	}
+
+	// Bind the quotes to the user and store them so CreateTrip can look up the chosen one
+	for _, fare := range fares {
+		fare.UserID = userID
+		fare.Pickup = pickup
+		fare.Destination = destination
+		fare.Waypoints = waypoints
+		if pickupAt != nil {
+			// The price holds for the scheduled ride, so the quote gets longer to be booked
+			fare.PickupAt = pickupAt
+			fare.ExpiresAt = time.Now().Add(s.cfg.ScheduledQuoteValidity)
+		}
+		if promo != nil {
+			applyPromotion(fare, promo)
+		}
+
+		token, err := s.signFare(fare)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to sign fare: %w", err)
+		}
+		fare.Token = token
+	}
+
+	if err := s.fareRepo.SaveMany(ctx, fares); err != nil {
+		return nil, nil, fmt.Errorf("failed to save fares: %w", err)
+	}
+
 	return route, fares, nil
 }
 
 // CreateTrip creates a new trip with the selected fare
-func (s *TripServiceImpl) CreateTrip(ctx context.Context, userID string, fareID string, pickup, destination *types.Coordinate) (*types.Trip, error) {
-	// First, get the route and fares to find the selected fare
-	route, fares, err := s.PreviewTrip(ctx, userID, pickup, destination)
+func (s *TripServiceImpl) CreateTrip(ctx context.Context, userID string, fareID string, fareToken string, promoCode string) (*types.Trip, error) {
+	// The signed token proves the fare was quoted to this user and has not been altered or expired
+	selectedFare, err := s.verifiedFare(ctx, userID, fareID, fareToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// Find the selected fare
-	var selectedFare *types.RouteFare
-	for _, fare := range fares {
-		if fare.ID == fareID {
-			selectedFare = fare
-			break
+	// A ride booked ahead must still leave time to find a driver
+	var schedule *types.TripSchedule
+	if selectedFare.PickupAt != nil {
+		if err := s.validatePickupTime(*selectedFare.PickupAt); err != nil {
+			return nil, err
 		}
+		schedule = s.newTripSchedule(*selectedFare.PickupAt)
 	}
 
-	if selectedFare == nil {
-		return nil, fmt.Errorf("fare not found: %s", fareID)
+	// The promo is part of the signed quote, so another code needs a new quote
+	if promoCode != "" && normalizePromoCode(promoCode) != selectedFare.PromoCode {
+		return nil, fmt.Errorf("%w: %s was not quoted for this fare, preview the trip with it", domain.ErrPromoNotApplicable, promoCode)
 	}
+	promoCode = selectedFare.PromoCode
 
-	// Check if fare has expired
-	if time.Now().After(selectedFare.ExpiresAt) {
-		return nil, fmt.Errorf("fare has expired")
+	// Pool riders share a trip going the same way if one can take them
+	if selectedFare.PackageSlug == types.CarPackagePool {
+		trip, err := s.joinPool(ctx, userID, selectedFare, promoCode)
+		if err != nil || trip != nil {
+			return trip, err
+		}
 	}
 
 	// Create trip
 	trip := &types.Trip{
-		ID:          uuid.New().String(),
-		UserID:      userID,
-		Status:      types.TripStatusCreated,
-		Route:       route,
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Status:       types.TripStatusCreated,
+		Pickup:       selectedFare.Pickup,
+		Destination:  selectedFare.Destination,
+		Stops:        newTripStops(selectedFare.Waypoints),
+		Route:        selectedFare.Route,
 		SelectedFare: selectedFare,
+		Schedule:     schedule,
+	}
+	if schedule != nil {
+		trip.Status = types.TripStatusScheduled
+	}
+	if selectedFare.PackageSlug == types.CarPackagePool {
+		trip.Stops, trip.Riders = newPool(userID, selectedFare)
+	}
+
+	// Save to database together with its promo redemption and event, so no trip exists that nobody hears
+	// about and no code is redeemed for a trip that does not exist
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.redeemPromo(ctx, userID, selectedFare, promoCode, trip.ID); err != nil {
+			return err
+		}
+
+		if err := s.repo.Create(ctx, trip); err != nil {
+			return fmt.Errorf("failed to create trip: %w", err)
+		}
+
+		// Scheduled trips are dispatched later by ProcessScheduledTrips
+		if schedule != nil {
+			return s.eventPublisher.PublishTripScheduled(ctx, trip)
+		}
+		return s.eventPublisher.PublishTripCreated(ctx, trip)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Save to database
-	if err := s.repo.Create(ctx, trip); err != nil {
-		return nil, fmt.Errorf("failed to create trip: %w", err)
+	if schedule != nil {
+		return trip, nil
 	}
 
-	// Publish trip created event
-	if err := s.eventPublisher.PublishTripCreated(ctx, trip); err != nil {
-		// Log error but don't fail the trip creation
-		fmt.Printf("Warning: failed to publish trip created event: %v\n", err)
+	// Offer the trip to the closest available driver. The trip is booked either way; if this fails it is
+	// dispatched again by RedispatchStalled.
+	if err := s.dispatchNext(ctx, trip); err != nil {
+		log.Printf("Failed to dispatch trip %s, leaving it to the sweep: %v", trip.ID, err)
 	}
 
 	return trip, nil
@@ -102,36 +286,48 @@ func (s *TripServiceImpl) CreateTrip(ctx context.Context, userID string, fareID
 // HandleDriverResponse processes a driver's accept/decline response
 func (s *TripServiceImpl) HandleDriverResponse(ctx context.Context, tripID string, driverID string, accepted bool) error {
 	// Get trip from database
-	trip, err := s.repo.GetByID(ctx, tripID)
+	trip, err := s.getTrip(ctx, tripID)
 	if err != nil {
-		return fmt.Errorf("failed to get trip: %w", err)
+		return err
 	}
 
-	if trip == nil {
-		return fmt.Errorf("trip not found: %s", tripID)
+	if accepted {
+		return s.acceptTrip(ctx, trip, driverID)
 	}
+	return s.declineTrip(ctx, trip, driverID)
+}
 
-	if accepted {
-		// Update trip status to driver assigned
-		if err := s.repo.UpdateStatus(ctx, tripID, types.TripStatusDriverAssigned); err != nil {
-			return fmt.Errorf("failed to update trip status: %w", err)
-		}
+// UpdateDriverLocation records a driver's latest location so it can be offered trips
+func (s *TripServiceImpl) UpdateDriverLocation(ctx context.Context, driver *types.Driver) error {
+	if driver.ID == "" {
+		return fmt.Errorf("driver ID is required")
+	}
 
-		// Get updated trip
-		trip, err = s.repo.GetByID(ctx, tripID)
-		if err != nil {
-			return fmt.Errorf("failed to get updated trip: %w", err)
-		}
+	if err := s.driverRepo.Upsert(ctx, driver); err != nil {
+		return fmt.Errorf("failed to update driver: %w", err)
+	}
 
-		// Publish driver assigned event
-		if err := s.eventPublisher.PublishDriverAssigned(ctx, trip); err != nil {
-			return fmt.Errorf("failed to publish driver assigned event: %w", err)
+	// Keep the trace of the trip the driver is on, if any, to meter the final fare
+	if driver.Location != nil {
+		point := &types.TracePoint{Location: driver.Location, RecordedAt: time.Now()}
+		if err := s.repo.AppendTrace(ctx, driver.ID, point); err != nil {
+			return fmt.Errorf("failed to record trip trace: %w", err)
 		}
-	} else {
-		// Driver declined - for now, we'll just log it
-		// In a real system, we might want to find another driver or mark the trip as needing a new driver
-		fmt.Printf("Driver %s declined trip %s\n", driverID, tripID)
 	}
 
 	return nil
 }
+
+// getTrip loads a trip, returning ErrTripNotFound if it does not exist
+func (s *TripServiceImpl) getTrip(ctx context.Context, tripID string) (*types.Trip, error) {
+	trip, err := s.repo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if trip == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotFound, tripID)
+	}
+
+	return trip, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/mmcloughlin/geohash"
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/pkg/types"
+)
+
+// surgeCellPrecision matches the geohash cells drivers are bucketed in (~4.9km x 4.9km)
+const surgeCellPrecision = 5
+
+// SurgeConfig holds the parameters of surge pricing
+type SurgeConfig struct {
+	// Window is how far back rider demand is counted
+	Window time.Duration
+
+	// MinDemand is how many riders a cell needs before it can surge
+	MinDemand int64
+
+	// Sensitivity is how much the multiplier grows per rider per available driver above 1
+	Sensitivity float64
+
+	// MaxMultiplier caps the multiplier
+	MaxMultiplier float64
+}
+
+// DefaultSurgeConfig returns a SurgeConfig with sensible default values
+func DefaultSurgeConfig() SurgeConfig {
+	return SurgeConfig{
+		Window:        5 * time.Minute,
+		MinDemand:     3,
+		Sensitivity:   0.25,
+		MaxMultiplier: 2.5,
+	}
+}
+
+// SurgeService computes surge multipliers from rider demand per geohash cell and the driver supply
+// dispatch can draw on for it
+type SurgeService struct {
+	cfg        SurgeConfig
+	surgeRepo  domain.SurgeRepository
+	driverRepo domain.DriverRepository
+}
+
+// NewSurgeService creates a new surge service
+func NewSurgeService(cfg SurgeConfig, surgeRepo domain.SurgeRepository, driverRepo domain.DriverRepository) domain.SurgePricer {
+	return &SurgeService{
+		cfg:        cfg,
+		surgeRepo:  surgeRepo,
+		driverRepo: driverRepo,
+	}
+}
+
+// RecordDemand notes that a rider is looking for a ride from the pickup
+func (s *SurgeService) RecordDemand(ctx context.Context, userID string, pickup *types.Coordinate) error {
+	return s.surgeRepo.RecordDemand(ctx, surgeCell(pickup), userID)
+}
+
+// Multiplier returns the surge multiplier for the cell of the pickup, 1 when there is no surge.
+// Demand is counted in the cell, supply in the cell and its neighbours.
+func (s *SurgeService) Multiplier(ctx context.Context, pickup *types.Coordinate) (float64, error) {
+	cell := surgeCell(pickup)
+
+	demand, err := s.surgeRepo.CountDemand(ctx, cell, time.Now().Add(-s.cfg.Window))
+	if err != nil {
+		return 1, err
+	}
+
+	// Supply is counted wherever dispatch looks for drivers, not only in the pickup's cell
+	supply, err := s.driverRepo.CountAvailable(ctx, pickup)
+	if err != nil {
+		return 1, err
+	}
+
+	return surgeMultiplier(s.cfg, demand, supply), nil
+}
+
+// surgeMultiplier grows linearly with the rider to driver ratio above 1, rounded to one decimal so
+// it reads as e.g. 1.4x. It is capped at MaxMultiplier after rounding, so rounding never exceeds the cap.
+func surgeMultiplier(cfg SurgeConfig, demand, supply int64) float64 {
+	if demand < cfg.MinDemand {
+		return 1
+	}
+
+	ratio := float64(demand) / float64(max(supply, 1))
+	multiplier := math.Round((1+(ratio-1)*cfg.Sensitivity)*10) / 10
+
+	return min(max(multiplier, 1), cfg.MaxMultiplier)
+}
+
+func surgeCell(pickup *types.Coordinate) string {
+	return geohash.EncodeWithPrecision(pickup.Latitude, pickup.Longitude, surgeCellPrecision)
+}
@@ -0,0 +1,38 @@
+package service
+
+import "testing"
+
+func TestSurgeMultiplier(t *testing.T) {
+	cfg := DefaultSurgeConfig() // MinDemand 3, Sensitivity 0.25, MaxMultiplier 2.5
+
+	capped := cfg
+	capped.MaxMultiplier = 1.75
+
+	tests := []struct {
+		name           string
+		cfg            SurgeConfig
+		demand, supply int64
+		want           float64
+	}{
+		{"below minimum demand", cfg, 2, 0, 1},
+		{"as many riders as drivers", cfg, 3, 3, 1},
+		{"more drivers than riders", cfg, 3, 10, 1},
+		{"no drivers counts as one", cfg, 3, 0, 1.5},
+		{"1.0833 rounds to 1.1", cfg, 4, 3, 1.1},
+		{"1.1875 rounds to 1.2", cfg, 7, 4, 1.2},
+		{"1.3125 rounds to 1.3", cfg, 9, 4, 1.3},
+		{"1.375 rounds half up to 1.4", cfg, 5, 2, 1.4},
+		{"1.5625 rounds to 1.6", cfg, 13, 4, 1.6},
+		{"capped at the maximum", cfg, 100, 1, 2.5},
+		{"rounding does not exceed the cap", capped, 100, 1, 1.75},
+		{"rounding below the cap", capped, 8, 4, 1.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := surgeMultiplier(tt.cfg, tt.demand, tt.supply); got != tt.want {
+				t.Errorf("surgeMultiplier(demand %d, supply %d) = %v, want %v", tt.demand, tt.supply, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// OutboxMessage is an event stored with the trip change that caused it, until the relay publishes it
+type OutboxMessage struct {
+	ID          string     `json:"id" bson:"_id"` // also the AMQP message ID
+	RoutingKey  string     `json:"routingKey" bson:"routing_key"`
+	AggregateID string     `json:"aggregateId,omitempty" bson:"aggregate_id,omitempty"` // trip the event is about, published in order per trip
+	Body        []byte     `json:"body" bson:"body"`
+	CreatedAt   time.Time  `json:"createdAt" bson:"created_at"`
+	Attempts    int        `json:"attempts" bson:"attempts"`                            // publishes tried so far
+	LockedUntil *time.Time `json:"lockedUntil,omitempty" bson:"locked_until,omitempty"` // claimed by a relay until then
+	SentAt      *time.Time `json:"sentAt,omitempty" bson:"sent_at,omitempty"`
+	FailedAt    *time.Time `json:"failedAt,omitempty" bson:"failed_at,omitempty"` // given up on, see Error
+	Error       string     `json:"error,omitempty" bson:"error,omitempty"`
+}
@@ -0,0 +1,38 @@
+package types
+
+import "time"
+
+// DiscountType tells how a promotion reduces a fare
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+)
+
+// Promotion is a promo code riders can apply to a fare.
+// Zero limits and zero validity bounds mean unlimited.
+type Promotion struct {
+	Code             string           `json:"code" bson:"_id"`
+	DiscountType     DiscountType     `json:"discountType" bson:"discount_type"`
+	PercentOff       int64            `json:"percentOff,omitempty" bson:"percent_off,omitempty"`              // for percentage discounts
+	AmountOffCents   int64            `json:"amountOffCents,omitempty" bson:"amount_off_cents,omitempty"`     // for fixed discounts
+	MaxDiscountCents int64            `json:"maxDiscountCents,omitempty" bson:"max_discount_cents,omitempty"` // caps percentage discounts
+	FirstRideOnly    bool             `json:"firstRideOnly" bson:"first_ride_only"`
+	PerUserLimit     int64            `json:"perUserLimit" bson:"per_user_limit"`
+	GlobalLimit      int64            `json:"globalLimit" bson:"global_limit"`
+	Redemptions      int64            `json:"redemptions" bson:"redemptions"`
+	PackageSlugs     []CarPackageSlug `json:"packageSlugs,omitempty" bson:"package_slugs,omitempty"` // empty means every package
+	ValidFrom        time.Time        `json:"validFrom,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil       time.Time        `json:"validUntil,omitempty" bson:"valid_until,omitempty"`
+}
+
+// PromoRedemption records a promo code used for a trip
+type PromoRedemption struct {
+	TripID          string    `json:"tripID" bson:"_id"`
+	Code            string    `json:"code" bson:"code"`
+	UserID          string    `json:"userID" bson:"user_id"`
+	DiscountInCents int64     `json:"discountInCents" bson:"discount_in_cents"`
+	FirstRide       bool      `json:"firstRide,omitempty" bson:"first_ride,omitempty"` // redeemed a first ride promotion
+	RedeemedAt      time.Time `json:"redeemedAt" bson:"redeemed_at"`
+}
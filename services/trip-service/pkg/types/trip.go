@@ -6,42 +6,140 @@ import "time"
 type TripStatus string
 
 const (
-	TripStatusPending      TripStatus = "pending"
-	TripStatusCreated      TripStatus = "created"
-	TripStatusDriverFound  TripStatus = "driver_found"
+	TripStatusPending        TripStatus = "pending"
+	TripStatusScheduled      TripStatus = "scheduled"
+	TripStatusCreated        TripStatus = "created"
+	TripStatusDriverFound    TripStatus = "driver_found"
 	TripStatusDriverAssigned TripStatus = "driver_assigned"
-	TripStatusInProgress   TripStatus = "in_progress"
-	TripStatusCompleted    TripStatus = "completed"
-	TripStatusCancelled    TripStatus = "cancelled"
+	TripStatusInProgress     TripStatus = "in_progress"
+	TripStatusCompleted      TripStatus = "completed"
+	TripStatusCancelled      TripStatus = "cancelled"
+)
+
+// TripActor identifies which side of a trip performed an action
+type TripActor string
+
+const (
+	TripActorRider  TripActor = "rider"
+	TripActorDriver TripActor = "driver"
+	TripActorSystem TripActor = "system" // the trip service itself, e.g. giving up on finding a driver
 )
 
 // CarPackageSlug represents the type of vehicle package
 type CarPackageSlug string
 
 const (
-	CarPackageSedan   CarPackageSlug = "sedan"
-	CarPackageSUV     CarPackageSlug = "suv"
+	CarPackageSedan  CarPackageSlug = "sedan"
+	CarPackageSUV    CarPackageSlug = "suv"
 	CarPackageVAN    CarPackageSlug = "van"
 	CarPackageLuxury CarPackageSlug = "luxury"
+	CarPackagePool   CarPackageSlug = "pool" // shared with other riders going the same way, driven by sedans
 )
 
 // Trip represents a ride-sharing trip
 type Trip struct {
-	ID          string      `json:"id" bson:"_id"`
-	UserID      string      `json:"userID" bson:"user_id"`
-	Status      TripStatus  `json:"status" bson:"status"`
-	Route       *Route      `json:"route" bson:"route"`
-	SelectedFare *RouteFare `json:"selectedFare,omitempty" bson:"selected_fare,omitempty"`
-	Driver      *Driver     `json:"driver,omitempty" bson:"driver,omitempty"`
-	CreatedAt   time.Time   `json:"createdAt" bson:"created_at"`
-	UpdatedAt   time.Time   `json:"updatedAt" bson:"updated_at"`
+	ID               string             `json:"id" bson:"_id"`
+	UserID           string             `json:"userID" bson:"user_id"`
+	Status           TripStatus         `json:"status" bson:"status"`
+	Pickup           *Coordinate        `json:"pickup,omitempty" bson:"pickup,omitempty"`
+	Destination      *Coordinate        `json:"destination,omitempty" bson:"destination,omitempty"`
+	Stops            []*TripStop        `json:"stops,omitempty" bson:"stops,omitempty"`   // ordered stops between pickup and destination; every pickup and drop-off on pool trips
+	Riders           []*PoolRider       `json:"riders,omitempty" bson:"riders,omitempty"` // riders sharing a pool trip, the first one being UserID
+	Route            *Route             `json:"route" bson:"route"`
+	SelectedFare     *RouteFare         `json:"selectedFare,omitempty" bson:"selected_fare,omitempty"`
+	Driver           *Driver            `json:"driver,omitempty" bson:"driver,omitempty"`
+	Schedule         *TripSchedule      `json:"schedule,omitempty" bson:"schedule,omitempty"` // set for rides booked ahead
+	DriverAssignedAt *time.Time         `json:"driverAssignedAt,omitempty" bson:"driver_assigned_at,omitempty"`
+	Cancellation     *Cancellation      `json:"cancellation,omitempty" bson:"cancellation,omitempty"`
+	StartedAt        *time.Time         `json:"startedAt,omitempty" bson:"started_at,omitempty"`
+	CompletedAt      *time.Time         `json:"completedAt,omitempty" bson:"completed_at,omitempty"`
+	Trace            []*TracePoint      `json:"-" bson:"trace,omitempty"` // driver locations while in progress
+	FinalFare        *FinalFare         `json:"finalFare,omitempty" bson:"final_fare,omitempty"`
+	DispatchAttempts []*DispatchAttempt `json:"dispatchAttempts,omitempty" bson:"dispatch_attempts,omitempty"`
+	CreatedAt        time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt        time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// TripSchedule holds the timing of a ride booked for a future pickup
+type TripSchedule struct {
+	PickupAt       time.Time  `json:"pickupAt" bson:"pickup_at"`
+	DispatchAt     time.Time  `json:"dispatchAt" bson:"dispatch_at"`                     // when the search for a driver starts
+	ReminderAt     *time.Time `json:"reminderAt,omitempty" bson:"reminder_at,omitempty"` // nil when booked too late for a reminder
+	ReminderSentAt *time.Time `json:"reminderSentAt,omitempty" bson:"reminder_sent_at,omitempty"`
+}
+
+// TripStop is an intermediate stop of a trip, in the order it is visited
+type TripStop struct {
+	Location  *Coordinate  `json:"location" bson:"location"`
+	ReachedAt *time.Time   `json:"reachedAt,omitempty" bson:"reached_at,omitempty"`
+	Kind      TripStopKind `json:"kind,omitempty" bson:"kind,omitempty"`      // pool trips only
+	UserID    string       `json:"userID,omitempty" bson:"user_id,omitempty"` // pool rider getting on or off
+}
+
+// TripStopKind tells whether a pool rider gets on or off at a stop
+type TripStopKind string
+
+const (
+	TripStopPickup  TripStopKind = "pickup"
+	TripStopDropoff TripStopKind = "dropoff"
+)
+
+// PoolRider is one of the riders sharing a pool trip
+type PoolRider struct {
+	UserID              string        `json:"userID" bson:"user_id"`
+	FareID              string        `json:"fareID" bson:"fare_id"`
+	FareInCents         int64         `json:"fareInCents" bson:"fare_in_cents"`      // upfront quote for the rider's own route, the most they pay
+	DirectDistance      float64       `json:"directDistance" bson:"direct_distance"` // meters, of the rider's own route
+	DirectDuration      float64       `json:"directDuration" bson:"direct_duration"` // seconds, of the rider's own route
+	JoinedAt            time.Time     `json:"joinedAt" bson:"joined_at"`
+	PickedUpAt          *time.Time    `json:"pickedUpAt,omitempty" bson:"picked_up_at,omitempty"`
+	DroppedOffAt        *time.Time    `json:"droppedOffAt,omitempty" bson:"dropped_off_at,omitempty"`
+	ChargedPriceInCents int64         `json:"chargedPriceInCents,omitempty" bson:"charged_price_in_cents,omitempty"` // share of the final fare
+	Cancellation        *Cancellation `json:"cancellation,omitempty" bson:"cancellation,omitempty"`                  // set when the rider left the pool
+}
+
+// TracePoint is a driver location recorded during a trip
+type TracePoint struct {
+	Location   *Coordinate `json:"location" bson:"location"`
+	RecordedAt time.Time   `json:"recordedAt" bson:"recorded_at"`
+}
+
+// FareAdjustmentReason explains the price charged at the end of a trip
+type FareAdjustmentReason string
+
+const (
+	FareAdjustmentWithinThreshold    FareAdjustmentReason = "within_threshold"    // upfront price kept
+	FareAdjustmentLongerTrip         FareAdjustmentReason = "longer_trip"         // metered price charged, above upfront
+	FareAdjustmentShorterTrip        FareAdjustmentReason = "shorter_trip"        // metered price charged, below upfront
+	FareAdjustmentInsufficientTrace  FareAdjustmentReason = "insufficient_trace"  // upfront price kept, too few locations
+	FareAdjustmentPricingUnavailable FareAdjustmentReason = "pricing_unavailable" // upfront price kept, pricing version unknown
+	FareAdjustmentPoolSplit          FareAdjustmentReason = "pool_split"          // metered price split between pool riders
+)
+
+// FinalFare is the price settled when a trip completes, next to the upfront quote
+type FinalFare struct {
+	UpfrontPriceInCents int64                `json:"upfrontPriceInCents" bson:"upfront_price_in_cents"`
+	MeteredPriceInCents int64                `json:"meteredPriceInCents" bson:"metered_price_in_cents"` // 0 when it could not be calculated
+	ChargedPriceInCents int64                `json:"chargedPriceInCents" bson:"charged_price_in_cents"`
+	Reason              FareAdjustmentReason `json:"reason" bson:"reason"`
+	DistanceMeters      float64              `json:"distanceMeters" bson:"distance_meters"`
+	DurationSeconds     float64              `json:"durationSeconds" bson:"duration_seconds"`
+	MeteredBreakdown    []*FareLineItem      `json:"meteredBreakdown,omitempty" bson:"metered_breakdown,omitempty"`
+	CalculatedAt        time.Time            `json:"calculatedAt" bson:"calculated_at"`
 }
 
 // Route represents the route information for a trip
 type Route struct {
-	Distance float64      `json:"distance" bson:"distance"` // in meters
-	Duration float64      `json:"duration" bson:"duration"` // in seconds
-	Geometry []*Geometry  `json:"geometry" bson:"geometry"`
+	Distance float64     `json:"distance" bson:"distance"` // in meters
+	Duration float64     `json:"duration" bson:"duration"` // in seconds
+	Geometry []*Geometry `json:"geometry" bson:"geometry"`
+	Legs     []*RouteLeg `json:"legs,omitempty" bson:"legs,omitempty"` // one per pair of consecutive stops
+}
+
+// RouteLeg is the part of a route between two consecutive stops
+type RouteLeg struct {
+	Distance float64 `json:"distance" bson:"distance"` // in meters
+	Duration float64 `json:"duration" bson:"duration"` // in seconds
 }
 
 // Geometry represents a geometry segment of the route
@@ -57,20 +155,109 @@ type Coordinate struct {
 
 // RouteFare represents pricing information for a route
 type RouteFare struct {
-	ID              string        `json:"id" bson:"_id"`
-	PackageSlug     CarPackageSlug `json:"packageSlug" bson:"package_slug"`
-	BasePrice       float64       `json:"basePrice" bson:"base_price"`
-	TotalPriceInCents int64        `json:"totalPriceInCents,omitempty" bson:"total_price_in_cents,omitempty"`
-	ExpiresAt       time.Time     `json:"expiresAt" bson:"expires_at"`
-	Route           *Route        `json:"route" bson:"route"`
+	ID                string          `json:"id" bson:"_id"`
+	UserID            string          `json:"userID" bson:"user_id"`
+	PackageSlug       CarPackageSlug  `json:"packageSlug" bson:"package_slug"`
+	Pickup            *Coordinate     `json:"pickup,omitempty" bson:"pickup,omitempty"`
+	Destination       *Coordinate     `json:"destination,omitempty" bson:"destination,omitempty"`
+	Waypoints         []*Coordinate   `json:"waypoints,omitempty" bson:"waypoints,omitempty"`
+	PickupAt          *time.Time      `json:"pickupAt,omitempty" bson:"pickup_at,omitempty"` // set when quoted for a scheduled ride
+	TotalPriceInCents int64           `json:"totalPriceInCents,omitempty" bson:"total_price_in_cents,omitempty"`
+	Breakdown         []*FareLineItem `json:"breakdown,omitempty" bson:"breakdown,omitempty"`
+	PricingRegion     string          `json:"pricingRegion,omitempty" bson:"pricing_region,omitempty"`
+	PricingVersion    int64           `json:"pricingVersion" bson:"pricing_version"`
+	SurgeMultiplier   float64         `json:"surgeMultiplier" bson:"surge_multiplier"` // 1 when there is no surge
+	PromoCode         string          `json:"promoCode,omitempty" bson:"promo_code,omitempty"`
+	DiscountInCents   int64           `json:"discountInCents,omitempty" bson:"discount_in_cents,omitempty"`
+	Token             string          `json:"token,omitempty" bson:"-"` // signed quote the rider books with, see shared/faretoken
+	ExpiresAt         time.Time       `json:"expiresAt" bson:"expires_at"`
+	Route             *Route          `json:"route" bson:"route"`
+}
+
+// FareLineItemType identifies a component of a fare
+type FareLineItemType string
+
+const (
+	FareLineItemBaseFee               FareLineItemType = "base_fee"
+	FareLineItemDistance              FareLineItemType = "distance"
+	FareLineItemTime                  FareLineItemType = "time"
+	FareLineItemStopFee               FareLineItemType = "stop_fee"
+	FareLineItemMinimumFareAdjustment FareLineItemType = "minimum_fare_adjustment"
+	FareLineItemSurge                 FareLineItemType = "surge"
+	FareLineItemBookingFee            FareLineItemType = "booking_fee"
+	FareLineItemPromoDiscount         FareLineItemType = "promo_discount" // negative amount
+)
+
+// FareLineItem is one itemised component of a fare; the items of a fare add up to its total
+type FareLineItem struct {
+	Type          FareLineItemType `json:"type" bson:"type"`
+	AmountInCents int64            `json:"amountInCents" bson:"amount_in_cents"`
+}
+
+// SumLineItems returns the total of the given fare line items
+func SumLineItems(items []*FareLineItem) int64 {
+	var total int64
+	for _, item := range items {
+		total += item.AmountInCents
+	}
+	return total
+}
+
+// PricingTable is one immutable version of the package rates of a service region
+type PricingTable struct {
+	ID              string                            `json:"id" bson:"_id"`
+	Region          string                            `json:"region" bson:"region"`
+	Version         int64                             `json:"version" bson:"version"`
+	GeohashPrefixes []string                          `json:"geohashPrefixes" bson:"geohash_prefixes"` // pickups in these cells belong to the region
+	Packages        map[CarPackageSlug]PackagePricing `json:"packages" bson:"packages"`
+	CreatedAt       time.Time                         `json:"createdAt" bson:"created_at"`
+}
+
+// PackagePricing holds the rates of a car package, all in cents
+type PackagePricing struct {
+	BaseFeeCents     int64 `json:"baseFeeCents" bson:"base_fee_cents"`
+	PerKmCents       int64 `json:"perKmCents" bson:"per_km_cents"`
+	PerMinuteCents   int64 `json:"perMinuteCents" bson:"per_minute_cents"`
+	MinimumFareCents int64 `json:"minimumFareCents" bson:"minimum_fare_cents"`
+	BookingFeeCents  int64 `json:"bookingFeeCents" bson:"booking_fee_cents"`
+	StopFeeCents     int64 `json:"stopFeeCents" bson:"stop_fee_cents"` // per intermediate stop
 }
 
 // Driver represents a driver assigned to a trip
 type Driver struct {
-	ID           string      `json:"id" bson:"_id"`
-	Name         string      `json:"name" bson:"name"`
-	Location     *Coordinate `json:"location" bson:"location"`
-	Geohash      string      `json:"geohash" bson:"geohash"`
-	ProfilePicture string    `json:"profilePicture" bson:"profile_picture"`
-	CarPlate     string      `json:"carPlate" bson:"car_plate"`
+	ID             string         `json:"id" bson:"_id"`
+	Name           string         `json:"name" bson:"name"`
+	Location       *Coordinate    `json:"location" bson:"location"`
+	Geohash        string         `json:"geohash" bson:"geohash"`
+	ProfilePicture string         `json:"profilePicture" bson:"profile_picture"`
+	CarPlate       string         `json:"carPlate" bson:"car_plate"`
+	PackageSlug    CarPackageSlug `json:"packageSlug,omitempty" bson:"package_slug,omitempty"`
+}
+
+// DispatchOutcome represents how a driver answered a trip offer
+type DispatchOutcome string
+
+const (
+	DispatchOutcomePending  DispatchOutcome = "pending"
+	DispatchOutcomeAccepted DispatchOutcome = "accepted"
+	DispatchOutcomeDeclined DispatchOutcome = "declined"
+	DispatchOutcomeExpired  DispatchOutcome = "expired"
+)
+
+// DispatchAttempt records a single offer of a trip to a driver
+type DispatchAttempt struct {
+	DriverID    string          `json:"driverID" bson:"driver_id"`
+	Outcome     DispatchOutcome `json:"outcome" bson:"outcome"`
+	OfferedAt   time.Time       `json:"offeredAt" bson:"offered_at"`
+	ExpiresAt   time.Time       `json:"expiresAt" bson:"expires_at"`
+	RespondedAt *time.Time      `json:"respondedAt,omitempty" bson:"responded_at,omitempty"`
+}
+
+// Cancellation records who cancelled a trip, why, and what it cost the rider
+type Cancellation struct {
+	CancelledBy TripActor `json:"cancelledBy" bson:"cancelled_by"`
+	ActorID     string    `json:"actorID" bson:"actor_id"`
+	Reason      string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	FeeInCents  int64     `json:"feeInCents" bson:"fee_in_cents"`
+	CancelledAt time.Time `json:"cancelledAt" bson:"cancelled_at"`
 }
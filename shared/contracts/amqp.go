@@ -1,25 +1,60 @@
 package contracts
 
-// AmqpMessage is the message structure for AMQP.
+import (
+	"encoding/json"
+	"time"
+)
+
+// AmqpMessage is the envelope every message on the trip exchange is published in
 type AmqpMessage struct {
-	OwnerID string `json:"ownerId"`
-	Data    []byte `json:"data"`
+	ID            string          `json:"id"`            // unique per message, also its AMQP MessageId
+	Type          string          `json:"type"`          // the routing key it was published with
+	SchemaVersion int             `json:"schemaVersion"` // version of the schema of Data
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Producer      string          `json:"producer"`                // service that published it
+	CorrelationID string          `json:"correlationId,omitempty"` // shared by every message caused by the same request
+	OwnerID       string          `json:"ownerId,omitempty"`       // user the message concerns
+	TraceParent   string          `json:"traceparent,omitempty"`   // W3C trace context of the span that published it
+	TraceState    string          `json:"tracestate,omitempty"`
+	Data          json.RawMessage `json:"data"`
 }
 
+// Schema versions of the data in an AmqpMessage, counted per routing key. When a payload changes
+// incompatibly, the version of its Topic is bumped and consumers keep reading the previous one until
+// every producer has been upgraded.
+const (
+	// SchemaVersionLegacy is given to bare payloads, published before the envelope existed
+	SchemaVersionLegacy = 0
+
+	// SchemaVersionEnvelope is the version of the payloads unchanged since the envelope was introduced
+	SchemaVersionEnvelope = 1
+)
+
 // Routing keys - using consistent event/command patterns
 const (
 	// Trip events (trip.event.*)
 	TripEventCreated             = "trip.event.created"
+	TripEventScheduled           = "trip.event.scheduled"
+	TripEventScheduledReminder   = "trip.event.scheduled_reminder"
 	TripEventDriverAssigned      = "trip.event.driver_assigned"
 	TripEventNoDriversFound      = "trip.event.no_drivers_found"
 	TripEventDriverNotInterested = "trip.event.driver_not_interested"
+	TripEventPoolRiderJoined     = "trip.event.pool_rider_joined"
+	TripEventPoolRiderLeft       = "trip.event.pool_rider_left"
+	TripEventCancelled           = "trip.event.cancelled"
+	TripEventStarted             = "trip.event.started"
+	TripEventStopReached         = "trip.event.stop_reached"
+	TripEventCompleted           = "trip.event.completed"
 
 	// Driver commands (driver.cmd.*)
-	DriverCmdTripRequest = "driver.cmd.trip_request"
-	DriverCmdTripAccept  = "driver.cmd.trip_accept"
-	DriverCmdTripDecline = "driver.cmd.trip_decline"
-	DriverCmdLocation    = "driver.cmd.location"
-	DriverCmdRegister    = "driver.cmd.register"
+	DriverCmdTripRequest     = "driver.cmd.trip_request"
+	DriverCmdTripAccept      = "driver.cmd.trip_accept"
+	DriverCmdTripDecline     = "driver.cmd.trip_decline"
+	DriverCmdTripStart       = "driver.cmd.trip_start"
+	DriverCmdTripStopReached = "driver.cmd.trip_stop_reached"
+	DriverCmdTripComplete    = "driver.cmd.trip_complete"
+	DriverCmdLocation        = "driver.cmd.location"
+	DriverCmdRegister        = "driver.cmd.register"
 
 	// Payment events (payment.event.*)
 	PaymentEventSessionCreated = "payment.event.session_created"
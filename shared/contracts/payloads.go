@@ -0,0 +1,196 @@
+package contracts
+
+import (
+	"time"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+)
+
+// Payloads published under each routing key, named after its constant. Keys sharing a shape embed it,
+// so each key still has a type of its own for its Topic. Field names are camelCase throughout, as in
+// web/src/contracts.ts.
+
+// Aggregate is implemented by the payloads about a single trip. Their events are published in the order
+// they happened per trip.
+type Aggregate interface {
+	AggregateID() string
+}
+
+// TripPayload is the trip as it is after the event, sent as the bare trip JSON
+type TripPayload struct {
+	*tripTypes.Trip
+}
+
+// AggregateID returns the ID of the trip
+func (p TripPayload) AggregateID() string {
+	if p.Trip == nil {
+		return ""
+	}
+	return p.Trip.ID
+}
+
+// TripEventCreatedData is published on trip.event.created
+type TripEventCreatedData struct{ TripPayload }
+
+// TripEventScheduledData is published on trip.event.scheduled
+type TripEventScheduledData struct{ TripPayload }
+
+// TripEventScheduledReminderData is published on trip.event.scheduled_reminder
+type TripEventScheduledReminderData struct{ TripPayload }
+
+// TripEventDriverAssignedData is published on trip.event.driver_assigned
+type TripEventDriverAssignedData struct{ TripPayload }
+
+// TripEventPoolRiderJoinedData is published on trip.event.pool_rider_joined
+type TripEventPoolRiderJoinedData struct{ TripPayload }
+
+// TripEventPoolRiderLeftData is published on trip.event.pool_rider_left
+type TripEventPoolRiderLeftData struct{ TripPayload }
+
+// TripEventCancelledData is published on trip.event.cancelled
+type TripEventCancelledData struct{ TripPayload }
+
+// TripEventStartedData is published on trip.event.started
+type TripEventStartedData struct{ TripPayload }
+
+// TripEventStopReachedData is published on trip.event.stop_reached
+type TripEventStopReachedData struct{ TripPayload }
+
+// TripEventCompletedData is published on trip.event.completed
+type TripEventCompletedData struct{ TripPayload }
+
+// TripEventNoDriversFoundData is published on trip.event.no_drivers_found.
+// Before schema version 2 it was snake_case, see legacyNoDriversFound.
+type TripEventNoDriversFoundData struct {
+	TripID string `json:"tripID"`
+	UserID string `json:"userID"`
+}
+
+// AggregateID returns the ID of the trip
+func (d TripEventNoDriversFoundData) AggregateID() string {
+	return d.TripID
+}
+
+// legacyNoDriversFound is trip.event.no_drivers_found up to schema version 1
+type legacyNoDriversFound struct {
+	TripID string `json:"trip_id"`
+	UserID string `json:"user_id"`
+}
+
+// TripEventDriverNotInterestedData is published on trip.event.driver_not_interested
+type TripEventDriverNotInterestedData struct {
+	DriverID string          `json:"driverID"`
+	Trip     *tripTypes.Trip `json:"trip"`
+}
+
+// AggregateID returns the ID of the trip
+func (d TripEventDriverNotInterestedData) AggregateID() string {
+	return tripID(d.Trip)
+}
+
+// DriverCmdTripRequestData is published on driver.cmd.trip_request, offering a trip to a driver
+type DriverCmdTripRequestData struct {
+	DriverID       string          `json:"driverID"`
+	Trip           *tripTypes.Trip `json:"trip"`
+	OfferExpiresAt *time.Time      `json:"offerExpiresAt,omitempty"`
+}
+
+// AggregateID returns the ID of the trip
+func (d DriverCmdTripRequestData) AggregateID() string {
+	return tripID(d.Trip)
+}
+
+// DriverTripResponse is a driver's answer to a trip offer
+type DriverTripResponse struct {
+	TripID   string `json:"tripID"`
+	RiderID  string `json:"riderID"`
+	DriverID string `json:"driverID"`
+	Accepted bool   `json:"accepted"`
+}
+
+// AggregateID returns the ID of the trip
+func (r DriverTripResponse) AggregateID() string {
+	return r.TripID
+}
+
+// DriverCmdTripAcceptData is published on driver.cmd.trip_accept
+type DriverCmdTripAcceptData struct{ DriverTripResponse }
+
+// DriverCmdTripDeclineData is published on driver.cmd.trip_decline
+type DriverCmdTripDeclineData struct{ DriverTripResponse }
+
+// DriverTripCommand is a driver starting, reaching a stop of or completing a trip
+type DriverTripCommand struct {
+	TripID    string                `json:"tripID"`
+	DriverID  string                `json:"driverID"`
+	Location  *tripTypes.Coordinate `json:"location,omitempty"`
+	StopIndex int                   `json:"stopIndex"` // for driver.cmd.trip_stop_reached
+}
+
+// AggregateID returns the ID of the trip
+func (c DriverTripCommand) AggregateID() string {
+	return c.TripID
+}
+
+// DriverCmdTripStartData is published on driver.cmd.trip_start
+type DriverCmdTripStartData struct{ DriverTripCommand }
+
+// DriverCmdTripStopReachedData is published on driver.cmd.trip_stop_reached
+type DriverCmdTripStopReachedData struct{ DriverTripCommand }
+
+// DriverCmdTripCompleteData is published on driver.cmd.trip_complete
+type DriverCmdTripCompleteData struct{ DriverTripCommand }
+
+// DriverPayload is the driver's profile and last known location, sent as the bare driver JSON
+type DriverPayload struct {
+	*tripTypes.Driver
+}
+
+// DriverCmdLocationData is published on driver.cmd.location
+type DriverCmdLocationData struct{ DriverPayload }
+
+// DriverCmdRegisterData is published on driver.cmd.register
+type DriverCmdRegisterData struct{ DriverPayload }
+
+// PaymentCmdCreateSessionData is published on payment.cmd.create_session
+type PaymentCmdCreateSessionData struct {
+	TripID   string  `json:"tripID"`
+	UserID   string  `json:"userID"`
+	DriverID string  `json:"driverID"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// PaymentEventSessionCreatedData is published on payment.event.session_created
+type PaymentEventSessionCreatedData struct {
+	TripID    string  `json:"tripID"`
+	SessionID string  `json:"sessionID"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+}
+
+// PaymentStatus is the outcome of a payment session
+type PaymentStatus struct {
+	TripID    string `json:"tripID"`
+	UserID    string `json:"userID"`
+	SessionID string `json:"sessionID"`
+}
+
+// PaymentEventSuccessData is published on payment.event.success
+type PaymentEventSuccessData struct{ PaymentStatus }
+
+// PaymentEventFailedData is published on payment.event.failed
+type PaymentEventFailedData struct {
+	PaymentStatus
+	Reason string `json:"reason,omitempty"`
+}
+
+// PaymentEventCancelledData is published on payment.event.cancelled
+type PaymentEventCancelledData struct{ PaymentStatus }
+
+func tripID(trip *tripTypes.Trip) string {
+	if trip == nil {
+		return ""
+	}
+	return trip.ID
+}
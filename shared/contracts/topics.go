@@ -0,0 +1,134 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrUnknownRoutingKey is returned for a routing key no payload is registered for
+	ErrUnknownRoutingKey = errors.New("unknown routing key")
+
+	// ErrWrongPayload is returned when publishing a payload of another type than the one registered for its key
+	ErrWrongPayload = errors.New("wrong payload type for routing key")
+
+	// ErrUnsupportedSchema is returned for messages of a schema version this build cannot read yet
+	ErrUnsupportedSchema = errors.New("unsupported schema version")
+)
+
+// Topic binds a routing key to the type of the payload published under it and the schema version of
+// that type, so publishing or subscribing with the wrong payload does not compile
+type Topic[T any] struct {
+	Key     string
+	Version int
+
+	// legacy reads the payload of versions before Version, when its shape was different
+	legacy func(version int, data []byte) (T, error)
+}
+
+// Decode reads the payload of a message of the given schema version
+func (t Topic[T]) Decode(version int, data []byte) (T, error) {
+	var payload T
+	if version > t.Version {
+		return payload, fmt.Errorf("%w: %s version %d", ErrUnsupportedSchema, t.Key, version)
+	}
+	if version < t.Version && t.legacy != nil {
+		return t.legacy(version, data)
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return payload, fmt.Errorf("failed to decode %s: %w", t.Key, err)
+	}
+	return payload, nil
+}
+
+// Topics, one per routing key
+var (
+	TripEventCreatedTopic             = register[TripEventCreatedData](TripEventCreated, SchemaVersionEnvelope)
+	TripEventScheduledTopic           = register[TripEventScheduledData](TripEventScheduled, SchemaVersionEnvelope)
+	TripEventScheduledReminderTopic   = register[TripEventScheduledReminderData](TripEventScheduledReminder, SchemaVersionEnvelope)
+	TripEventDriverAssignedTopic      = register[TripEventDriverAssignedData](TripEventDriverAssigned, SchemaVersionEnvelope)
+	TripEventNoDriversFoundTopic      = registerLegacy(TripEventNoDriversFound, 2, decodeLegacyNoDriversFound)
+	TripEventDriverNotInterestedTopic = register[TripEventDriverNotInterestedData](TripEventDriverNotInterested, SchemaVersionEnvelope)
+	TripEventPoolRiderJoinedTopic     = register[TripEventPoolRiderJoinedData](TripEventPoolRiderJoined, SchemaVersionEnvelope)
+	TripEventPoolRiderLeftTopic       = register[TripEventPoolRiderLeftData](TripEventPoolRiderLeft, SchemaVersionEnvelope)
+	TripEventCancelledTopic           = register[TripEventCancelledData](TripEventCancelled, SchemaVersionEnvelope)
+	TripEventStartedTopic             = register[TripEventStartedData](TripEventStarted, SchemaVersionEnvelope)
+	TripEventStopReachedTopic         = register[TripEventStopReachedData](TripEventStopReached, SchemaVersionEnvelope)
+	TripEventCompletedTopic           = register[TripEventCompletedData](TripEventCompleted, SchemaVersionEnvelope)
+
+	DriverCmdTripRequestTopic     = register[DriverCmdTripRequestData](DriverCmdTripRequest, SchemaVersionEnvelope)
+	DriverCmdTripAcceptTopic      = register[DriverCmdTripAcceptData](DriverCmdTripAccept, SchemaVersionEnvelope)
+	DriverCmdTripDeclineTopic     = register[DriverCmdTripDeclineData](DriverCmdTripDecline, SchemaVersionEnvelope)
+	DriverCmdTripStartTopic       = register[DriverCmdTripStartData](DriverCmdTripStart, SchemaVersionEnvelope)
+	DriverCmdTripStopReachedTopic = register[DriverCmdTripStopReachedData](DriverCmdTripStopReached, SchemaVersionEnvelope)
+	DriverCmdTripCompleteTopic    = register[DriverCmdTripCompleteData](DriverCmdTripComplete, SchemaVersionEnvelope)
+	DriverCmdLocationTopic        = register[DriverCmdLocationData](DriverCmdLocation, SchemaVersionEnvelope)
+	DriverCmdRegisterTopic        = register[DriverCmdRegisterData](DriverCmdRegister, SchemaVersionEnvelope)
+
+	PaymentEventSessionCreatedTopic = register[PaymentEventSessionCreatedData](PaymentEventSessionCreated, SchemaVersionEnvelope)
+	PaymentEventSuccessTopic        = register[PaymentEventSuccessData](PaymentEventSuccess, SchemaVersionEnvelope)
+	PaymentEventFailedTopic         = register[PaymentEventFailedData](PaymentEventFailed, SchemaVersionEnvelope)
+	PaymentEventCancelledTopic      = register[PaymentEventCancelledData](PaymentEventCancelled, SchemaVersionEnvelope)
+	PaymentCmdCreateSessionTopic    = register[PaymentCmdCreateSessionData](PaymentCmdCreateSession, SchemaVersionEnvelope)
+)
+
+// registration is what the registry knows about a routing key
+type registration struct {
+	payload reflect.Type
+	version int
+}
+
+// registry holds the payload type of every routing key, for publishers that only know the key at runtime
+var registry = map[string]registration{}
+
+func register[T any](key string, version int) Topic[T] {
+	if _, ok := registry[key]; ok {
+		panic("contracts: routing key registered twice: " + key)
+	}
+	registry[key] = registration{payload: reflect.TypeFor[T](), version: version}
+	return Topic[T]{Key: key, Version: version}
+}
+
+func registerLegacy[T any](key string, version int, legacy func(version int, data []byte) (T, error)) Topic[T] {
+	topic := register[T](key, version)
+	topic.legacy = legacy
+	return topic
+}
+
+// CheckPayload returns ErrWrongPayload unless data, or what it points to, is the payload type registered
+// for the routing key, and ErrUnknownRoutingKey if none is
+func CheckPayload(key string, data any) error {
+	reg, ok := registry[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRoutingKey, key)
+	}
+
+	t := reflect.TypeOf(data)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != reg.payload {
+		return fmt.Errorf("%w: %s takes %v, not %v", ErrWrongPayload, key, reg.payload, t)
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version payloads are published with under the routing key
+func SchemaVersion(key string) (int, error) {
+	reg, ok := registry[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownRoutingKey, key)
+	}
+	return reg.version, nil
+}
+
+// decodeLegacyNoDriversFound reads the snake_case trip.event.no_drivers_found of before version 2
+func decodeLegacyNoDriversFound(version int, data []byte) (TripEventNoDriversFoundData, error) {
+	var legacy legacyNoDriversFound
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return TripEventNoDriversFoundData{}, fmt.Errorf("failed to decode %s version %d: %w", TripEventNoDriversFound, version, err)
+	}
+	return TripEventNoDriversFoundData(legacy), nil
+}
@@ -0,0 +1,97 @@
+/*
+Package faretoken signs and verifies fare quotes.
+A token binds a quoted fare to its rider, route, package, price and expiry, so a booking
+can be checked without trusting the client or reaching the fare store.
+*/
+package faretoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"ride-sharing/shared/types"
+)
+
+var (
+	// ErrInvalid is returned for malformed tokens and tokens whose signature does not match
+	ErrInvalid = errors.New("invalid fare token")
+
+	// ErrExpired is returned for correctly signed tokens used after their expiry
+	ErrExpired = errors.New("fare token has expired")
+)
+
+// Claims are the fare details a token vouches for
+type Claims struct {
+	FareID            string             `json:"fid"`
+	UserID            string             `json:"uid"`
+	Pickup            types.Coordinate   `json:"pu"`
+	Destination       types.Coordinate   `json:"de"`
+	Waypoints         []types.Coordinate `json:"wp,omitempty"`
+	PackageSlug       string             `json:"pkg"`
+	TotalPriceInCents int64              `json:"amt"`
+	PromoCode         string             `json:"promo,omitempty"`
+	DiscountInCents   int64              `json:"disc,omitempty"` // already deducted from TotalPriceInCents
+	PickupAt          int64              `json:"pat,omitempty"`  // Unix timestamp of a scheduled pickup
+	ExpiresAt         int64              `json:"exp"`            // Unix timestamp
+}
+
+// Signer creates and checks HMAC-SHA256 signed fare tokens
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a signer using the given secret
+func NewSigner(secret string) *Signer {
+	return &Signer{key: []byte(secret)}
+}
+
+// Sign returns a token of the form base64url(claims).base64url(signature)
+func (s *Signer) Sign(claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// Verify checks the signature and expiry of a token and returns its claims
+func (s *Signer) Verify(token string, now time.Time) (*Claims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(signature, s.mac(encoded)) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalid
+	}
+
+	if now.Unix() > claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &claims, nil
+}
+
+func (s *Signer) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,129 @@
+package faretoken
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ride-sharing/shared/types"
+)
+
+var now = time.Unix(1_700_000_000, 0)
+
+func testClaims() Claims {
+	return Claims{
+		FareID:            "fare-1",
+		UserID:            "user-1",
+		Pickup:            types.Coordinate{Latitude: 52.52, Longitude: 13.40},
+		Destination:       types.Coordinate{Latitude: 52.50, Longitude: 13.45},
+		Waypoints:         []types.Coordinate{{Latitude: 52.51, Longitude: 13.42}},
+		PackageSlug:       "sedan",
+		TotalPriceInCents: 1850,
+		PromoCode:         "WELCOME",
+		DiscountInCents:   200,
+		ExpiresAt:         now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+func sign(t *testing.T, s *Signer, claims Claims) string {
+	t.Helper()
+	token, err := s.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return token
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	s := NewSigner("secret")
+	claims := testClaims()
+
+	got, err := s.Verify(sign(t, s, claims), now)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !reflect.DeepEqual(*got, claims) {
+		t.Errorf("Verify = %+v, want %+v", *got, claims)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	s := NewSigner("secret")
+	token := sign(t, s, testClaims())
+	encoded, sig, _ := strings.Cut(token, ".")
+
+	// Re-encode the claims with a lower price but keep the original signature
+	cheaper := testClaims()
+	cheaper.TotalPriceInCents = 1
+	payload, err := json.Marshal(cheaper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repriced := base64.RawURLEncoding.EncodeToString(payload) + "." + sig
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"changed claims", repriced},
+		{"changed signature", encoded + "." + base64.RawURLEncoding.EncodeToString([]byte("forged"))},
+		{"missing signature", encoded},
+		{"signature not base64", encoded + ".!!"},
+		{"claims not base64", "!!." + sig},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(tt.token, now); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Verify = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsWrongSecret(t *testing.T) {
+	token := sign(t, NewSigner("secret"), testClaims())
+
+	if _, err := NewSigner("other").Verify(token, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Verify = %v, want ErrInvalid", err)
+	}
+}
+
+func TestVerifyExpiry(t *testing.T) {
+	s := NewSigner("secret")
+	claims := testClaims()
+	token := sign(t, s, claims)
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		{"before expiry", expiresAt.Add(-time.Second), nil},
+		{"at expiry", expiresAt, nil},
+		{"after expiry", expiresAt.Add(time.Second), ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(token, tt.now); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksSignatureBeforeExpiry(t *testing.T) {
+	claims := testClaims()
+	claims.ExpiresAt = now.Add(-time.Minute).Unix()
+	token := sign(t, NewSigner("secret"), claims)
+
+	// A forged token must not learn anything from the expiry check
+	if _, err := NewSigner("other").Verify(token, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Verify = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Package messaging keeps a RabbitMQ connection alive for the services using it.
+When the connection drops it reconnects with backoff, declares the topology it was given again
+and resubscribes the consumers, so a broker restart does not need a service restart.
+*/
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/shared/retry"
+)
+
+var (
+	// ErrNotConnected is returned while the connection is down and being re-established
+	ErrNotConnected = errors.New("not connected to RabbitMQ")
+
+	// errClosed stops reconnecting once the connection was closed on purpose
+	errClosed = errors.New("connection closed")
+)
+
+// Connection is a RabbitMQ connection that re-establishes itself
+type Connection struct {
+	uri      string
+	retryCfg retry.Config
+
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	ready     chan struct{} // closed while connected
+	publishCh *amqp.Channel // in confirm mode, reopened once closed
+	returns   chan amqp.Return
+	topology  topology
+	closing   bool
+
+	publishMu sync.Mutex // one publish awaits its confirm at a time
+}
+
+// Dial connects to RabbitMQ, retrying with the backoff of cfg while the broker is starting up.
+// Afterwards the connection is watched and re-established, waiting per retry.Backoff between attempts,
+// until ctx is cancelled or Close is called.
+func Dial(ctx context.Context, uri string, cfg retry.Config) (*Connection, error) {
+	c := &Connection{
+		uri:      uri,
+		retryCfg: cfg,
+		ready:    make(chan struct{}),
+	}
+
+	var closed chan *amqp.Error
+	err := retry.WithBackoff(ctx, cfg, func() error {
+		var err error
+		closed, err = c.connect()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	go c.watch(ctx, closed)
+
+	return c, nil
+}
+
+// connect dials the broker and declares the topology on the new connection before making it available
+func (c *Connection) connect() (chan *amqp.Error, error) {
+	conn, err := amqp.Dial(c.uri)
+	if err != nil {
+		return nil, err
+	}
+	closed := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closing {
+		conn.Close()
+		return nil, errClosed
+	}
+
+	if err := c.topology.declare(ch); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	ch.Close()
+
+	c.conn = conn
+	close(c.ready)
+
+	return closed, nil
+}
+
+// watch re-establishes the connection whenever the broker closes it
+func (c *Connection) watch(ctx context.Context, closed chan *amqp.Error) {
+	for {
+		var reason *amqp.Error
+		select {
+		case <-ctx.Done():
+			return
+		case reason = <-closed:
+		}
+
+		c.mu.Lock()
+		if c.closing {
+			c.mu.Unlock()
+			return
+		}
+		c.ready = make(chan struct{})
+		c.publishCh = nil
+		c.mu.Unlock()
+
+		log.Printf("RabbitMQ connection lost: %v, reconnecting", reason)
+
+		for attempt := 1; ; attempt++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retry.Backoff(c.retryCfg, attempt)):
+			}
+
+			var err error
+			closed, err = c.connect()
+			if errors.Is(err, errClosed) {
+				return
+			}
+			if err == nil {
+				break
+			}
+			log.Printf("Failed to reconnect to RabbitMQ (attempt %d): %v", attempt, err)
+		}
+
+		log.Println("Reconnected to RabbitMQ")
+	}
+}
+
+// Ready returns a channel that is closed once the connection is up
+func (c *Connection) Ready() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ready
+}
+
+// Channel opens a channel on the current connection. Channels do not survive a reconnect:
+// callers open a new one once the old one is closed.
+func (c *Connection) Channel() (*amqp.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.channel()
+}
+
+func (c *Connection) channel() (*amqp.Channel, error) {
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil, ErrNotConnected
+	}
+	return c.conn.Channel()
+}
+
+// Publish sends a mandatory message on a channel shared by all callers and waits until the broker
+// has taken responsibility for it. It returns an UnroutableError when no queue is bound for the
+// message, and ErrNacked when the broker refused it.
+func (c *Connection) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	ch, returns, err := c.publishChannel()
+	if err != nil {
+		return err
+	}
+
+	// Returns left over from a publish that gave up waiting belong to other messages
+	for len(returns) > 0 {
+		<-returns
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(
+		ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		true,       // mandatory
+		false,      // immediate
+		msg,
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The broker sends a message back before it acks it, so any return is in by now
+	for len(returns) > 0 {
+		returned := <-returns
+		if returned.MessageId == msg.MessageId {
+			return &UnroutableError{Exchange: exchange, RoutingKey: routingKey, Reason: returned.ReplyText}
+		}
+	}
+
+	if !acked {
+		return ErrNacked
+	}
+	return nil
+}
+
+// publishChannel returns the channel messages are published on, opening a new one in confirm mode
+// if it was closed, e.g. by a reconnect
+func (c *Connection) publishChannel() (*amqp.Channel, chan amqp.Return, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.publishCh != nil && !c.publishCh.IsClosed() {
+		return c.publishCh, c.returns, nil
+	}
+
+	ch, err := c.channel()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, nil, fmt.Errorf("failed to put channel in confirm mode: %w", err)
+	}
+
+	c.publishCh = ch
+	c.returns = ch.NotifyReturn(make(chan amqp.Return, 16))
+	return c.publishCh, c.returns, nil
+}
+
+// Close closes the connection for good
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closing = true
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
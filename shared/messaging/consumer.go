@@ -0,0 +1,149 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/shared/retry"
+)
+
+// ConsumeOptions tunes a subscription to a queue
+type ConsumeOptions struct {
+	Prefetch  int  // unacknowledged deliveries at most, 0 for no limit
+	AutoAck   bool // deliveries count as acknowledged once sent
+	Exclusive bool // no other consumer may use the queue
+}
+
+// Consume subscribes to a queue on a channel of its own and returns its deliveries.
+// Whenever the channel or the connection closes, it subscribes again once possible, so the
+// returned channel keeps delivering across reconnects. Deliveries received before a reconnect
+// can no longer be acknowledged; the broker redelivers them.
+//
+// Once ctx is cancelled the subscription is cancelled, and the returned channel is closed after
+// the deliveries the broker had already sent. Unless AutoAck is set, the AMQP channel of a subscription
+// is only closed once every delivery received on it has been acknowledged, rejected or requeued, so
+// deliveries still queued up by the caller can be settled.
+func (c *Connection) Consume(ctx context.Context, queue string, opts ConsumeOptions) (<-chan amqp.Delivery, error) {
+	ch, msgs, err := c.subscribe(queue, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan amqp.Delivery)
+	go func() {
+		defer close(out)
+
+		for {
+			current := ch
+			stop := context.AfterFunc(ctx, func() {
+				if err := current.Cancel(queue, false); err != nil {
+					log.Printf("Failed to cancel consumer of %s: %v", queue, err)
+				}
+			})
+			var inFlight sync.WaitGroup
+			for msg := range msgs {
+				if !opts.AutoAck {
+					inFlight.Add(1)
+					msg.Acknowledger = &settledAcknowledger{Acknowledger: msg.Acknowledger, settled: inFlight.Done}
+				}
+				out <- msg
+			}
+			stop()
+
+			// Acknowledgements travel over the channel, so it stays open until the last one is sent
+			inFlight.Wait()
+			ch.Close()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Printf("Consumer of %s stopped, subscribing again", queue)
+			if ch, msgs, err = c.resubscribe(ctx, queue, opts); err != nil {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// subscribe opens a channel and starts consuming the queue on it
+func (c *Connection) subscribe(queue string, opts ConsumeOptions) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	ch, err := c.Channel()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := ch.Qos(opts.Prefetch, 0, false); err != nil {
+		ch.Close()
+		return nil, nil, fmt.Errorf("failed to set prefetch on %s: %w", queue, err)
+	}
+
+	msgs, err := ch.Consume(
+		queue,          // queue
+		queue,          // consumer
+		opts.AutoAck,   // auto-ack
+		opts.Exclusive, // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
+	)
+	if err != nil {
+		ch.Close()
+		return nil, nil, fmt.Errorf("failed to consume %s: %w", queue, err)
+	}
+
+	return ch, msgs, nil
+}
+
+// resubscribe waits for the connection and subscribes again, backing off between failed attempts
+func (c *Connection) resubscribe(ctx context.Context, queue string, opts ConsumeOptions) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-c.Ready():
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(retry.Backoff(c.retryCfg, attempt)):
+		}
+
+		ch, msgs, err := c.subscribe(queue, opts)
+		if err == nil {
+			log.Printf("Subscribed to %s again", queue)
+			return ch, msgs, nil
+		}
+		log.Printf("Failed to subscribe to %s (attempt %d): %v", queue, attempt, err)
+	}
+}
+
+// settledAcknowledger reports when the delivery it belongs to has been settled, whichever way.
+// Deliveries are settled one at a time, never with multiple set.
+type settledAcknowledger struct {
+	amqp.Acknowledger
+	once    sync.Once
+	settled func()
+}
+
+func (a *settledAcknowledger) Ack(tag uint64, multiple bool) error {
+	defer a.once.Do(a.settled)
+	return a.Acknowledger.Ack(tag, multiple)
+}
+
+func (a *settledAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	defer a.once.Do(a.settled)
+	return a.Acknowledger.Nack(tag, multiple, requeue)
+}
+
+func (a *settledAcknowledger) Reject(tag uint64, requeue bool) error {
+	defer a.once.Do(a.settled)
+	return a.Acknowledger.Reject(tag, requeue)
+}
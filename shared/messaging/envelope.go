@@ -0,0 +1,81 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/tracing"
+)
+
+// NewMessage wraps data in the envelope published for eventType, with the schema version of its topic.
+// data must be the payload type registered for eventType, else contracts.ErrWrongPayload is returned.
+// The envelope carries the correlation ID of ctx and a new span of its trace; without them the message
+// starts a trace of its own and correlates with itself.
+func NewMessage(ctx context.Context, producer, eventType, ownerID string, data any) (*contracts.AmqpMessage, error) {
+	if err := contracts.CheckPayload(eventType, data); err != nil {
+		return nil, err
+	}
+	version, err := contracts.SchemaVersion(eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", eventType, err)
+	}
+
+	msg := &contracts.AmqpMessage{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		SchemaVersion: version,
+		OccurredAt:    time.Now(),
+		Producer:      producer,
+		CorrelationID: tracing.CorrelationID(ctx),
+		OwnerID:       ownerID,
+		Data:          payload,
+	}
+	if msg.CorrelationID == "" {
+		msg.CorrelationID = msg.ID
+	}
+
+	tc, ok := tracing.FromContext(ctx)
+	if ok {
+		tc = tc.Child()
+	} else {
+		tc = tracing.NewTrace()
+	}
+	msg.TraceParent, msg.TraceState = tc.TraceParent, tc.TraceState
+
+	return msg, nil
+}
+
+// DecodeMessage reads the envelope of a delivery. A bare payload, published before the envelope existed,
+// comes back as SchemaVersionLegacy with the whole body as its data.
+func DecodeMessage(body []byte) (*contracts.AmqpMessage, error) {
+	var msg contracts.AmqpMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	if msg.SchemaVersion == contracts.SchemaVersionLegacy || msg.Data == nil {
+		return &contracts.AmqpMessage{SchemaVersion: contracts.SchemaVersionLegacy, Data: body}, nil
+	}
+	return &msg, nil
+}
+
+// ContextOf returns a context continuing the trace and correlation ID of a received message,
+// so the messages published while handling it belong to the same request
+func ContextOf(ctx context.Context, msg *contracts.AmqpMessage) context.Context {
+	if tc, ok := tracing.Parse(msg.TraceParent, msg.TraceState); ok {
+		ctx = tracing.WithTrace(ctx, tc)
+	}
+	if msg.CorrelationID != "" {
+		ctx = tracing.WithCorrelationID(ctx, msg.CorrelationID)
+	}
+	return ctx
+}
@@ -0,0 +1,37 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNacked is returned when the broker refused to take responsibility for a published message
+	ErrNacked = errors.New("message nacked by the broker")
+
+	// ErrUnroutable is matched by every UnroutableError
+	ErrUnroutable = errors.New("message routed to no queue")
+
+	// ErrMalformed is returned for a delivery whose envelope or payload cannot be decoded
+	ErrMalformed = errors.New("malformed message")
+
+	// ErrNoSubscriber is returned for a delivery with a routing key no handler is subscribed to
+	ErrNoSubscriber = errors.New("no subscriber for routing key")
+)
+
+// UnroutableError is returned when the broker sent a published message back because no queue is
+// bound to its exchange for its routing key
+type UnroutableError struct {
+	Exchange   string
+	RoutingKey string
+	Reason     string
+}
+
+func (e *UnroutableError) Error() string {
+	return fmt.Sprintf("%v: %q on exchange %q (%s)", ErrUnroutable, e.RoutingKey, e.Exchange, e.Reason)
+}
+
+// Is lets callers match any UnroutableError with errors.Is(err, ErrUnroutable)
+func (e *UnroutableError) Is(target error) bool {
+	return target == ErrUnroutable
+}
@@ -0,0 +1,123 @@
+package messaging
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Exchange describes a durable exchange
+type Exchange struct {
+	Name string
+	Kind string // e.g. "topic" or "direct"
+	Args amqp.Table
+}
+
+// Queue describes a queue and the bindings that route messages to it
+type Queue struct {
+	Name      string
+	Transient bool // not durable, and deleted once its last consumer is gone
+	Exclusive bool // only usable by this connection, deleted with it
+	Args      amqp.Table
+	Bindings  []Binding
+}
+
+// Binding routes the messages of an exchange with a routing key to a queue
+type Binding struct {
+	Exchange   string
+	RoutingKey string
+}
+
+// topology is everything declared through a Connection, in the order it was declared
+type topology struct {
+	exchanges []Exchange
+	queues    []Queue
+}
+
+// declare declares every exchange, then every queue with its bindings
+func (t *topology) declare(ch *amqp.Channel) error {
+	for _, exchange := range t.exchanges {
+		if err := declareExchange(ch, exchange); err != nil {
+			return err
+		}
+	}
+	for _, queue := range t.queues {
+		if err := declareQueue(ch, queue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeclareExchange declares an exchange now and again after every reconnect
+func (c *Connection) DeclareExchange(exchange Exchange) error {
+	return c.declare(func(ch *amqp.Channel) error {
+		if err := declareExchange(ch, exchange); err != nil {
+			return err
+		}
+		c.topology.exchanges = append(c.topology.exchanges, exchange)
+		return nil
+	})
+}
+
+// DeclareQueue declares a queue and its bindings now and again after every reconnect
+func (c *Connection) DeclareQueue(queue Queue) error {
+	return c.declare(func(ch *amqp.Channel) error {
+		if err := declareQueue(ch, queue); err != nil {
+			return err
+		}
+		c.topology.queues = append(c.topology.queues, queue)
+		return nil
+	})
+}
+
+// declare runs fn on a channel of its own, so a declaration the broker refuses closes nothing else
+func (c *Connection) declare(fn func(ch *amqp.Channel) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch, err := c.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	return fn(ch)
+}
+
+func declareExchange(ch *amqp.Channel, exchange Exchange) error {
+	err := ch.ExchangeDeclare(
+		exchange.Name, // name
+		exchange.Kind, // type
+		true,          // durable
+		false,         // auto-deleted
+		false,         // internal
+		false,         // no-wait
+		exchange.Args, // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", exchange.Name, err)
+	}
+	return nil
+}
+
+func declareQueue(ch *amqp.Channel, queue Queue) error {
+	_, err := ch.QueueDeclare(
+		queue.Name,       // name
+		!queue.Transient, // durable
+		queue.Transient,  // delete when unused
+		queue.Exclusive,  // exclusive
+		false,            // no-wait
+		queue.Args,       // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", queue.Name, err)
+	}
+
+	for _, binding := range queue.Bindings {
+		if err := ch.QueueBind(queue.Name, binding.RoutingKey, binding.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s to %s: %w", queue.Name, binding.RoutingKey, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"ride-sharing/shared/contracts"
+)
+
+// Sender publishes data in the versioned envelope under a routing key, on behalf of ownerID.
+// It is implemented by the publisher of each service; the payload is checked against the registry of
+// shared/contracts, since the routing key is only known at runtime.
+type Sender interface {
+	Send(ctx context.Context, routingKey, ownerID string, data any) error
+}
+
+// Publish sends data under the routing key of topic. The topic fixes the payload type, so publishing the
+// wrong one does not compile.
+func Publish[T any](ctx context.Context, sender Sender, topic contracts.Topic[T], ownerID string, data T) error {
+	return sender.Send(ctx, topic.Key, ownerID, data)
+}
+
+// Router hands the deliveries of a queue to the handler subscribed to their routing key
+type Router struct {
+	handlers map[string]func(ctx context.Context, msg *contracts.AmqpMessage) error
+}
+
+// NewRouter creates a router without subscriptions
+func NewRouter() *Router {
+	return &Router{
+		handlers: make(map[string]func(ctx context.Context, msg *contracts.AmqpMessage) error),
+	}
+}
+
+// Subscribe has the router hand the messages of topic to handler, with their payload decoded into the
+// topic's type from whichever schema version it was published with, and a context continuing their
+// trace and correlation ID
+func Subscribe[T any](r *Router, topic contracts.Topic[T], handler func(ctx context.Context, msg *contracts.AmqpMessage, data T) error) {
+	r.handlers[topic.Key] = func(ctx context.Context, msg *contracts.AmqpMessage) error {
+		data, err := topic.Decode(msg.SchemaVersion, msg.Data)
+		if errors.Is(err, contracts.ErrUnsupportedSchema) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		return handler(ContextOf(ctx, msg), msg, data)
+	}
+}
+
+// Keys returns the routing keys subscribed to, to bind the queue with
+func (r *Router) Keys() []string {
+	keys := make([]string, 0, len(r.handlers))
+	for key := range r.handlers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Handle decodes a delivery and runs the handler subscribed to its type, returning its error.
+// A delivery that cannot be read returns ErrMalformed, one of a newer schema version
+// contracts.ErrUnsupportedSchema, and one nobody subscribed to ErrNoSubscriber.
+//
+// The type is taken from the envelope rather than the AMQP routing key, which no longer names the event
+// once a message comes back from a retry queue or is replayed from a dead-letter queue. Only legacy bare
+// payloads, which have no type, fall back to the routing key.
+func (r *Router) Handle(ctx context.Context, msg amqp.Delivery) error {
+	envelope, err := DecodeMessage(msg.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	eventType := envelope.Type
+	if eventType == "" {
+		eventType = msg.RoutingKey
+	}
+
+	handler, ok := r.handlers[eventType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoSubscriber, eventType)
+	}
+	return handler(ctx, envelope)
+}
@@ -6,6 +6,7 @@ package retry
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 )
@@ -25,13 +26,39 @@ func DefaultConfig() Config {
 	}
 }
 
+// Backoff returns how long to wait before the given retry attempt, counting from 1.
+// The wait starts at InitialWait and doubles with every attempt, capped at MaxWait.
+func Backoff(cfg Config, attempt int) time.Duration {
+	wait := cfg.InitialWait
+	for i := 1; i < attempt && wait < cfg.MaxWait; i++ {
+		wait *= 2
+	}
+	if wait > cfg.MaxWait {
+		wait = cfg.MaxWait
+	}
+	return wait
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so WithBackoff returns it straight away instead of retrying
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
 // WithBackoff executes the given operation with exponential backoff retry logic
 func WithBackoff(ctx context.Context, cfg Config, operation func() error) error {
 	var err error
-	wait := cfg.InitialWait
 
 	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
 		if attempt > 0 {
+			wait := Backoff(cfg, attempt)
 			log.Printf("Retry attempt %d/%d after %v", attempt, cfg.MaxRetries, wait)
 
 			select {
@@ -39,18 +66,17 @@ func WithBackoff(ctx context.Context, cfg Config, operation func() error) error
 				return ctx.Err()
 			case <-time.After(wait):
 			}
-
-			// Exponential backoff with max wait cap
-			wait *= 2
-			if wait > cfg.MaxWait {
-				wait = cfg.MaxWait
-			}
 		}
 
 		if err = operation(); err == nil {
 			return nil
 		}
 
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
 		log.Printf("Operation failed (attempt %d/%d): %v", attempt+1, cfg.MaxRetries, err)
 	}
 
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Extract returns a context carrying the trace context and correlation ID read with get, e.g. an
+// http.Header's Get. A missing or invalid traceparent starts a new trace.
+func Extract(ctx context.Context, get func(key string) string) context.Context {
+	tc, ok := Parse(get(TraceParentHeader), get(TraceStateHeader))
+	if !ok {
+		tc = NewTrace()
+	}
+	ctx = WithTrace(ctx, tc.Child())
+
+	if id := get(CorrelationIDHeader); id != "" {
+		ctx = WithCorrelationID(ctx, id)
+	}
+	return ctx
+}
+
+// UnaryServerInterceptor continues the trace and correlation ID of incoming gRPC calls
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = Extract(ctx, func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	})
+	return handler(ctx, req)
+}
+
+// UnaryClientInterceptor passes the trace and correlation ID of the context on to outgoing gRPC calls
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if tc, ok := FromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, TraceParentHeader, tc.TraceParent)
+		if tc.TraceState != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, TraceStateHeader, tc.TraceState)
+		}
+	}
+	if id := CorrelationID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, CorrelationIDHeader, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
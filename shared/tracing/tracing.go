@@ -0,0 +1,102 @@
+/*
+Package tracing carries a W3C trace context (https://www.w3.org/TR/trace-context/) and a correlation ID
+through a request: from HTTP headers and gRPC metadata into the context, and from the context into the
+messages published while handling it.
+*/
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Header names, shared by HTTP and gRPC metadata
+const (
+	TraceParentHeader   = "traceparent"
+	TraceStateHeader    = "tracestate"
+	CorrelationIDHeader = "x-correlation-id"
+)
+
+// TraceContext identifies the trace and the span a piece of work belongs to
+type TraceContext struct {
+	TraceParent string // version-traceid-spanid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+	TraceState  string // vendor specific, passed on unchanged
+}
+
+// NewTrace starts a new, sampled trace
+func NewTrace() TraceContext {
+	return TraceContext{TraceParent: fmt.Sprintf("00-%s-%s-01", randomHex(16), randomHex(8))}
+}
+
+// Parse validates a traceparent header and returns its trace context
+func Parse(traceParent, traceState string) (TraceContext, bool) {
+	parts := strings.Split(traceParent, "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return TraceContext{}, false
+	}
+	for _, part := range parts {
+		if _, err := hex.DecodeString(part); err != nil {
+			return TraceContext{}, false
+		}
+	}
+	if parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceParent: strings.ToLower(traceParent), TraceState: traceState}, true
+}
+
+// Child returns the trace context of a new span in the same trace
+func (tc TraceContext) Child() TraceContext {
+	parts := strings.Split(tc.TraceParent, "-")
+	if len(parts) != 4 {
+		return NewTrace()
+	}
+	return TraceContext{
+		TraceParent: fmt.Sprintf("%s-%s-%s-%s", parts[0], parts[1], randomHex(8), parts[3]),
+		TraceState:  tc.TraceState,
+	}
+}
+
+// TraceID returns the ID shared by every span of the trace
+func (tc TraceContext) TraceID() string {
+	parts := strings.Split(tc.TraceParent, "-")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1]
+}
+
+type traceKey struct{}
+
+type correlationKey struct{}
+
+// WithTrace returns a context carrying the trace context
+func WithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceKey{}, tc)
+}
+
+// FromContext returns the trace context carried by ctx, if any
+func FromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceKey{}).(TraceContext)
+	return tc, ok
+}
+
+// WithCorrelationID returns a context carrying the correlation ID
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or an empty string
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -1,9 +1,17 @@
 package types
 
+import "time"
+
 type Route struct {
 	Distance float64     `json:"distance"`
 	Duration float64     `json:"duration"`
 	Geometry []*Geometry `json:"geometry"`
+	Legs     []*RouteLeg `json:"legs,omitempty"`
+}
+
+type RouteLeg struct {
+	Distance float64 `json:"distance"`
+	Duration float64 `json:"duration"`
 }
 
 type Geometry struct {
@@ -14,3 +22,25 @@ type Coordinate struct {
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 }
+
+type RouteFare struct {
+	ID                string          `json:"id"`
+	PackageSlug       string          `json:"packageSlug"`
+	BasePrice         float64         `json:"basePrice"`
+	TotalPriceInCents int64           `json:"totalPriceInCents,omitempty"`
+	Breakdown         []*FareLineItem `json:"breakdown,omitempty"`
+	PricingRegion     string          `json:"pricingRegion,omitempty"`
+	PricingVersion    int64           `json:"pricingVersion"`
+	SurgeMultiplier   float64         `json:"surgeMultiplier"`
+	PromoCode         string          `json:"promoCode,omitempty"`
+	DiscountInCents   int64           `json:"discountInCents,omitempty"`
+	Token             string          `json:"token,omitempty"`
+	PickupAt          *time.Time      `json:"pickupAt,omitempty"` // set for rides booked ahead
+	ExpiresAt         time.Time       `json:"expiresAt"`
+	Route             *Route          `json:"route"`
+}
+
+type FareLineItem struct {
+	Type          string `json:"type"`
+	AmountInCents int64  `json:"amountInCents"`
+}
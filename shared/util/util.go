@@ -1,8 +1,27 @@
 package util
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // GetRandomAvatar returns a random avatar URL from the randomuser.me API
 func GetRandomAvatar(index int) string {
 	return fmt.Sprintf("https://randomuser.me/api/portraits/lego/%d.jpg", index)
 }
+
+// earthRadiusMeters is the mean radius of the Earth
+const earthRadiusMeters = 6371000.0
+
+// HaversineDistance returns the great-circle distance in meters between two coordinates
+func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
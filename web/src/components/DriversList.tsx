@@ -47,6 +47,9 @@ export function DriverList({ trip, onPackageSelect, onCancel }: DriverListProps)
                 </div>
                 <div className="text-right">
                   <p className="font-semibold">{price}</p>
+                  {fare.surgeMultiplier && fare.surgeMultiplier > 1 && (
+                    <p className="text-xs font-medium text-orange-600">{fare.surgeMultiplier.toFixed(1)}x surge</p>
+                  )}
                 </div>
               </div>
             );
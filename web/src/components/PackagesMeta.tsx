@@ -1,4 +1,4 @@
-import { Bus, Truck, Crown } from "lucide-react";
+import { Bus, Truck, Crown, Users } from "lucide-react";
 import { Car } from "lucide-react";
 import { CarPackageSlug } from "../types";
 
@@ -27,4 +27,9 @@ export const PackagesMeta: Record<CarPackageSlug, {
     icon: <Crown />,
     description: "Premium experience",
   },
+  [CarPackageSlug.POOL]: {
+    name: "Pool",
+    icon: <Users />,
+    description: "Share the ride, pay less",
+  },
 }
\ No newline at end of file
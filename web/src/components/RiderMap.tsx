@@ -114,10 +114,11 @@ export default function RiderMap({ onRouteSelected }: RiderMapProps) {
         const payload = {
             rideFareID: fare.id,
             userID: userID,
+            fareToken: fare.token,
         } as HTTPTripStartRequestPayload
 
-        if (!fare.id) {
-            alert("No Fare ID in the payload")
+        if (!fare.id || !fare.token) {
+            alert("No Fare ID or token in the payload")
             return
         }
 
@@ -12,13 +12,22 @@ export enum BackendEndpoints {
 export enum TripEvents {
   NoDriversFound = "trip.event.no_drivers_found",
   DriverAssigned = "trip.event.driver_assigned",
+  Started = "trip.event.started",
+  StopReached = "trip.event.stop_reached",
   Completed = "trip.event.completed",
   Cancelled = "trip.event.cancelled",
+  PoolRiderJoined = "trip.event.pool_rider_joined",
+  PoolRiderLeft = "trip.event.pool_rider_left",
   Created = "trip.event.created",
+  Scheduled = "trip.event.scheduled",
+  ScheduledReminder = "trip.event.scheduled_reminder",
   DriverLocation = "driver.cmd.location",
   DriverTripRequest = "driver.cmd.trip_request",
   DriverTripAccept = "driver.cmd.trip_accept",
   DriverTripDecline = "driver.cmd.trip_decline",
+  DriverTripStart = "driver.cmd.trip_start",
+  DriverTripStopReached = "driver.cmd.trip_stop_reached",
+  DriverTripComplete = "driver.cmd.trip_complete",
   DriverRegister = "driver.cmd.register",
   PaymentSessionCreated = "payment.event.session_created",
 }
@@ -31,18 +40,36 @@ export type ServerWsMessage =
   | DriverTripRequest
   | DriverRegisterRequest
   | TripCreatedRequest
+  | TripScheduledRequest
+  | PoolRidersChangedRequest
   | NoDriversFoundRequest;
 
 // Messages sent from the client to the server via the websocket
-export type ClientWsMessage = DriverResponseToTripResponse
+export type ClientWsMessage = DriverResponseToTripResponse | DriverTripLifecycleCommand
 
 interface TripCreatedRequest {
   type: TripEvents.Created;
   data: Trip;
 }
 
+interface TripScheduledRequest {
+  type: TripEvents.Scheduled | TripEvents.ScheduledReminder;
+  data: Trip;
+}
+
+interface PoolRidersChangedRequest {
+  type: TripEvents.PoolRiderJoined | TripEvents.PoolRiderLeft;
+  data: Trip;
+}
+
+export interface NoDriversFoundData {
+  tripID: string;
+  userID: string;
+}
+
 interface NoDriversFoundRequest {
   type: TripEvents.NoDriversFound;
+  data: NoDriversFoundData;
 }
 
 interface DriverRegisterRequest {
@@ -85,6 +112,14 @@ interface DriverResponseToTripResponse {
   };
 }
 
+interface DriverTripLifecycleCommand {
+  type: TripEvents.DriverTripStart | TripEvents.DriverTripStopReached | TripEvents.DriverTripComplete;
+  data: {
+    tripID: string;
+    stopIndex?: number; // for DriverTripStopReached
+  };
+}
+
 export interface HTTPTripPreviewResponse {
   route: Route;
   rideFares: RouteFare[];
@@ -93,12 +128,17 @@ export interface HTTPTripPreviewResponse {
 export interface HTTPTripStartRequestPayload {
   rideFareID: string;
   userID: string;
+  fareToken: string;
+  promoCode?: string;
 }
 
 export interface HTTPTripPreviewRequestPayload {
   userID: string;
   pickup: Coordinate;
   destination: Coordinate;
+  waypoints?: Coordinate[];
+  promoCode?: string;
+  pickupAt?: string; // ISO 8601, set to book the ride ahead
 }
 
 export function isValidTripEvent(event: string): event is TripEvents {
@@ -4,7 +4,10 @@ export interface Trip {
     status: string;
     selectedFare: RouteFare;
     route: Route;
+    stops?: TripStop[];
+    riders?: PoolRider[];
     driver?: Driver;
+    finalFare?: FinalFare;
     trip: Trip;
 }
 
@@ -24,6 +27,29 @@ export interface Route {
     }[],
     duration: number,
     distance: number,
+    legs?: RouteLeg[],
+}
+
+export interface RouteLeg {
+    duration: number,
+    distance: number,
+}
+
+export interface TripStop {
+    location: Coordinate,
+    reachedAt?: string,
+    kind?: "pickup" | "dropoff", // pool trips only
+    userID?: string,
+}
+
+export interface PoolRider {
+    userID: string,
+    fareID: string,
+    fareInCents: number,
+    joinedAt: string,
+    pickedUpAt?: string,
+    droppedOffAt?: string,
+    chargedPriceInCents?: number,
 }
 
 export enum CarPackageSlug {
@@ -31,6 +57,7 @@ export enum CarPackageSlug {
     SUV = "suv",
     VAN = "van",
     LUXURY = "luxury",
+    POOL = "pool",
 }
 
 export interface RouteFare {
@@ -38,10 +65,34 @@ export interface RouteFare {
     packageSlug: CarPackageSlug,
     basePrice: number,
     totalPriceInCents?: number,
+    breakdown?: FareLineItem[],
+    pricingRegion?: string,
+    pricingVersion?: number,
+    surgeMultiplier?: number,
+    promoCode?: string,
+    discountInCents?: number,
+    token?: string,
+    pickupAt?: string,
     expiresAt: Date,
     route: Route,
 }
 
+export interface FareLineItem {
+    type: string,
+    amountInCents: number,
+}
+
+export interface FinalFare {
+    upfrontPriceInCents: number,
+    meteredPriceInCents: number,
+    chargedPriceInCents: number,
+    reason: string,
+    distanceMeters: number,
+    durationSeconds: number,
+    meteredBreakdown?: FareLineItem[],
+    calculatedAt: string,
+}
+
 
 export interface HTTPTripStartResponse {
     tripID: string;